@@ -0,0 +1,24 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Command etcdv2mock serves an in-memory etcd v2 API, e.g. for running the
+// terraform test suites under examples/tests without a live cluster.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"terraform-provider-etcdv2/internal/etcdv2mock"
+)
+
+func main() {
+	var addr string
+
+	flag.StringVar(&addr, "addr", "127.0.0.1:4001", "address to serve the etcd v2 API on")
+	flag.Parse()
+
+	log.Printf("serving mock etcd v2 API on http://%s", addr)
+	log.Fatal(http.ListenAndServe(addr, etcdv2mock.New()))
+}
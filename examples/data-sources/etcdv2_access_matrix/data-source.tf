@@ -0,0 +1,5 @@
+data "etcdv2_access_matrix" "all" {}
+
+output "writers" {
+  value = [for e in data.etcdv2_access_matrix.all.entries : e if e.write]
+}
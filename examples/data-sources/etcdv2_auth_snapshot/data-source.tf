@@ -0,0 +1,6 @@
+data "etcdv2_auth_snapshot" "prod" {}
+
+resource "local_file" "auth" {
+  filename = "${path.module}/auth.json"
+  content  = data.etcdv2_auth_snapshot.prod.json
+}
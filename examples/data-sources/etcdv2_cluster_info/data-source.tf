@@ -0,0 +1 @@
+data "etcdv2_cluster_info" "this" {}
@@ -0,0 +1,8 @@
+data "etcdv2_connection_test" "preflight" {}
+
+check "etcd_connection" {
+  assert {
+    condition     = data.etcdv2_connection_test.preflight.passed
+    error_message = join("\n", data.etcdv2_connection_test.preflight.errors)
+  }
+}
@@ -0,0 +1,4 @@
+data "etcdv2_directory" "services" {
+  key       = "/services"
+  recursive = false
+}
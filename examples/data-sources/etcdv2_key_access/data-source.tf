@@ -0,0 +1,3 @@
+data "etcdv2_key_access" "foo" {
+  key = "/root/bar"
+}
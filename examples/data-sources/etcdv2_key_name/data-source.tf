@@ -0,0 +1,9 @@
+data "etcdv2_key_name" "config" {
+  for_each = toset(["/app/config/log_level", "/app/config/region"])
+
+  key = each.value
+}
+
+data "etcdv2_key_name" "log_level" {
+  name = "app__config__log_level"
+}
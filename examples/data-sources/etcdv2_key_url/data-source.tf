@@ -0,0 +1,4 @@
+data "etcdv2_key_url" "ready" {
+  endpoint = "https://etcd.example.com:2379"
+  key      = etcdv2_keyvalue.ready.key
+}
@@ -0,0 +1,4 @@
+data "etcdv2_queue" "jobs" {
+  key   = "/jobs"
+  limit = 10
+}
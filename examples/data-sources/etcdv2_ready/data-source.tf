@@ -0,0 +1,11 @@
+data "etcdv2_ready" "cluster" {
+  timeout = 600
+  roles   = ["app"]
+}
+
+resource "etcdv2_keyvalue" "config" {
+  key   = "/app/config"
+  value = "enabled"
+
+  depends_on = [data.etcdv2_ready.cluster]
+}
@@ -0,0 +1,18 @@
+data "etcdv2_role_diff" "admin" {
+  name = "admin"
+
+  permissions = [
+    {
+      key    = "/app/*"
+      access = "readwrite"
+    },
+  ]
+}
+
+output "admin_to_grant" {
+  value = data.etcdv2_role_diff.admin.to_grant
+}
+
+output "admin_to_revoke" {
+  value = data.etcdv2_role_diff.admin.to_revoke
+}
@@ -0,0 +1,4 @@
+data "etcdv2_roles_with_access" "db_password" {
+  key    = "/app/db/password"
+  access = "read"
+}
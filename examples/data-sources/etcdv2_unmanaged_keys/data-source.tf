@@ -0,0 +1,7 @@
+data "etcdv2_unmanaged_keys" "app" {
+  prefix = "/app"
+}
+
+output "stray_keys" {
+  value = data.etcdv2_unmanaged_keys.app.keys
+}
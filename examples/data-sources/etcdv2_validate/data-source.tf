@@ -0,0 +1,18 @@
+check "service_config" {
+  data "etcdv2_validate" "services" {
+    key = "/services"
+    json_schema = jsonencode({
+      type     = "object"
+      required = ["host", "port"]
+      properties = {
+        host = { type = "string" }
+        port = { type = "integer", minimum = 1, maximum = 65535 }
+      }
+    })
+  }
+
+  assert {
+    condition     = data.etcdv2_validate.services.valid
+    error_message = join("\n", data.etcdv2_validate.services.errors)
+  }
+}
@@ -0,0 +1,11 @@
+data "etcdv2_wait_for_members" "cluster" {
+  members = 3
+  timeout = 900
+}
+
+resource "etcdv2_keyvalue" "seed" {
+  key   = "/config/bootstrapped"
+  value = "true"
+
+  depends_on = [data.etcdv2_wait_for_members.cluster]
+}
@@ -0,0 +1,7 @@
+resource "etcdv2_auth_restore" "staging" {
+  snapshot = file("${path.module}/auth.json")
+
+  passwords = {
+    app = var.app_password
+  }
+}
@@ -0,0 +1,5 @@
+resource "etcdv2_directory" "preview" {
+  key              = "/preview/pr-123"
+  ttl              = 86400
+  refresh_on_apply = true
+}
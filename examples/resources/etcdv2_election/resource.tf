@@ -0,0 +1,5 @@
+resource "etcdv2_election" "primary" {
+  key       = "/app/primary"
+  candidate = "blue"
+  ttl       = 3600
+}
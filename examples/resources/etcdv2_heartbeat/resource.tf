@@ -0,0 +1,5 @@
+resource "etcdv2_heartbeat" "deployer" {
+  key   = "/app/deployer"
+  value = "terraform"
+  ttl   = 30
+}
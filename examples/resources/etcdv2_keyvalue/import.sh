@@ -0,0 +1,8 @@
+# Import a key
+terraform import etcdv2_keyvalue.hello_world /root/hello
+
+# Import a key only if the provider is configured for the prod cluster
+terraform import etcdv2_keyvalue.hello_world cluster=prod,key=/root/hello
+
+# Import a key only if it is still at modified index 42
+terraform import etcdv2_keyvalue.hello_world key=/root/hello,index=42
@@ -0,0 +1,5 @@
+# Adopt every key beneath a prefix
+terraform import etcdv2_keyvalues.app /app/config
+
+# Adopt a prefix only if the provider is configured for the prod cluster
+terraform import etcdv2_keyvalues.app cluster=prod,prefix=/app/config
@@ -0,0 +1,8 @@
+resource "etcdv2_keyvalues" "app" {
+  prefix = "/app/config"
+
+  data = {
+    "log_level"    = "info"
+    "db/max_conns" = "20"
+  }
+}
@@ -0,0 +1,4 @@
+resource "etcdv2_leadership_hint" "maintenance" {
+  member       = "etcd-0"
+  wait_timeout = 60
+}
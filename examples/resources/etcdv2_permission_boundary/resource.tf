@@ -0,0 +1,14 @@
+resource "etcdv2_permission_boundary" "team" {
+  name = "team-a"
+
+  permissions = [
+    {
+      key    = "/*"
+      access = "read"
+    },
+    {
+      key    = "/team-a/*"
+      access = "readwrite"
+    },
+  ]
+}
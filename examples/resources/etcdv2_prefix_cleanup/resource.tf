@@ -0,0 +1,4 @@
+resource "etcdv2_prefix_cleanup" "app" {
+  prefix       = "/app"
+  plan_preview = true
+}
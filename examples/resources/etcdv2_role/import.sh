@@ -0,0 +1,5 @@
+# Import a role by name
+terraform import etcdv2_role.app app
+
+# Import a role only if the provider is configured for the prod cluster
+terraform import etcdv2_role.app cluster=prod,name=app
@@ -0,0 +1,14 @@
+resource "etcdv2_role" "app" {
+  name = "app"
+
+  permissions = [
+    {
+      key    = "/app/*"
+      access = "readwrite"
+    },
+    {
+      key    = "/shared/*"
+      access = "read"
+    },
+  ]
+}
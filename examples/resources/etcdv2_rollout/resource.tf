@@ -0,0 +1,6 @@
+resource "etcdv2_rollout" "config" {
+  key           = "/app/config"
+  value         = jsonencode({ version = 42 })
+  confirm_value = "42"
+  timeout       = 120
+}
@@ -0,0 +1,5 @@
+# Import a user by name. The password is not returned by etcd and is set on the next apply
+terraform import etcdv2_user.app app
+
+# Import a user only if the provider is configured for the prod cluster
+terraform import etcdv2_user.app cluster=prod,name=app
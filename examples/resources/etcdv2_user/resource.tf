@@ -0,0 +1,6 @@
+resource "etcdv2_user" "app" {
+  name     = "app"
+  password = var.app_password
+
+  roles = [etcdv2_role.app.name]
+}
@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    etcdv2 = {
+      source = "github.com/retryW/etcdv2"
+    }
+  }
+}
+
+variable "host" {
+  type    = string
+  default = "http://127.0.0.1:4001"
+}
+
+provider "etcdv2" {
+  host = var.host
+}
+
+resource "etcdv2_directory" "preview" {
+  key = "/tests/directory/pr-123"
+  ttl = 3600
+}
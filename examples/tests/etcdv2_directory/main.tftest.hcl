@@ -0,0 +1,6 @@
+run "create" {
+  assert {
+    condition     = etcdv2_directory.preview.expiration != null
+    error_message = "A directory with a ttl should report its expiration"
+  }
+}
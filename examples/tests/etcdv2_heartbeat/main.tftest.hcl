@@ -0,0 +1,6 @@
+run "create" {
+  assert {
+    condition     = etcdv2_heartbeat.deployer.value == "terraform"
+    error_message = "The heartbeat key was not written"
+  }
+}
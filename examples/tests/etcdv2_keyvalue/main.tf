@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    etcdv2 = {
+      source = "github.com/retryW/etcdv2"
+    }
+  }
+}
+
+variable "host" {
+  type    = string
+  default = "http://127.0.0.1:4001"
+}
+
+provider "etcdv2" {
+  host = var.host
+}
+
+variable "value" {
+  type    = string
+  default = "world"
+}
+
+resource "etcdv2_keyvalue" "hello" {
+  key                        = "/tests/keyvalue/hello"
+  value                      = var.value
+  abort_on_concurrent_change = true
+}
@@ -0,0 +1,33 @@
+run "create" {
+  assert {
+    condition     = etcdv2_keyvalue.hello.value == "world"
+    error_message = "The key was not written with the configured value"
+  }
+
+  assert {
+    condition     = etcdv2_keyvalue.hello.prev_value == null
+    error_message = "A newly created key should have no previous value"
+  }
+}
+
+# The update is a compare-and-swap on the modified index from the create
+run "update" {
+  variables {
+    value = "there"
+  }
+
+  assert {
+    condition     = etcdv2_keyvalue.hello.value == "there"
+    error_message = "The key was not updated"
+  }
+
+  assert {
+    condition     = etcdv2_keyvalue.hello.prev_value == "world"
+    error_message = "The previous value was not recorded"
+  }
+
+  assert {
+    condition     = etcdv2_keyvalue.hello.modified_index > run.create.etcdv2_keyvalue.hello.modified_index
+    error_message = "The update did not advance the modified index"
+  }
+}
@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    etcdv2 = {
+      source = "github.com/retryW/etcdv2"
+    }
+  }
+}
+
+variable "host" {
+  type    = string
+  default = "http://127.0.0.1:4001"
+}
+
+provider "etcdv2" {
+  host = var.host
+}
+
+variable "data" {
+  type = map(string)
+  default = {
+    "log_level"    = "info"
+    "db/max_conns" = "20"
+  }
+}
+
+resource "etcdv2_keyvalues" "app" {
+  prefix       = "/tests/keyvalues"
+  data         = var.data
+  max_children = 2
+}
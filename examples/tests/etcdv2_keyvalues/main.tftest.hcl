@@ -0,0 +1,19 @@
+run "create" {
+  assert {
+    condition     = length(etcdv2_keyvalues.app.data) == 2
+    error_message = "Both keys should be written"
+  }
+}
+
+run "remove_key" {
+  variables {
+    data = {
+      "log_level" = "debug"
+    }
+  }
+
+  assert {
+    condition     = etcdv2_keyvalues.app.data["log_level"] == "debug"
+    error_message = "The remaining key was not updated"
+  }
+}
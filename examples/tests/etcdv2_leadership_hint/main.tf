@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    etcdv2 = {
+      source = "github.com/retryW/etcdv2"
+    }
+  }
+}
+
+variable "host" {
+  type    = string
+  default = "http://127.0.0.1:4001"
+}
+
+provider "etcdv2" {
+  host = var.host
+}
+
+resource "etcdv2_leadership_hint" "maintenance" {
+  member = "default"
+}
@@ -0,0 +1,7 @@
+# The mock server reports a single member named "default" as leader
+run "create" {
+  assert {
+    condition     = etcdv2_leadership_hint.maintenance.leader_name == "default"
+    error_message = "The leader was not reported"
+  }
+}
@@ -0,0 +1,28 @@
+terraform {
+  required_providers {
+    etcdv2 = {
+      source = "github.com/retryW/etcdv2"
+    }
+  }
+}
+
+variable "host" {
+  type    = string
+  default = "http://127.0.0.1:4001"
+}
+
+provider "etcdv2" {
+  host = var.host
+}
+
+resource "etcdv2_keyvalue" "seed" {
+  key   = "/tests/prefix_cleanup/seed"
+  value = "1"
+}
+
+resource "etcdv2_prefix_cleanup" "app" {
+  prefix       = "/tests/prefix_cleanup"
+  plan_preview = true
+
+  depends_on = [etcdv2_keyvalue.seed]
+}
@@ -0,0 +1,13 @@
+run "create" {
+  command = apply
+}
+
+# The preview is listed on the refresh after the seed key exists
+run "preview" {
+  command = plan
+
+  assert {
+    condition     = contains(etcdv2_prefix_cleanup.app.keys_to_remove, "/tests/prefix_cleanup/seed")
+    error_message = "The seed key should be listed for removal"
+  }
+}
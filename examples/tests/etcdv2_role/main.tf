@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    etcdv2 = {
+      source = "github.com/retryW/etcdv2"
+    }
+  }
+}
+
+variable "host" {
+  type    = string
+  default = "http://127.0.0.1:4001"
+}
+
+provider "etcdv2" {
+  host = var.host
+}
+
+variable "access" {
+  type    = string
+  default = "readwrite"
+}
+
+resource "etcdv2_role" "app" {
+  name = "tests-role"
+
+  permissions = [
+    {
+      key    = "/app/*"
+      access = var.access
+    },
+  ]
+}
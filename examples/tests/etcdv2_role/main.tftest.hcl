@@ -0,0 +1,17 @@
+run "create" {
+  assert {
+    condition     = one(etcdv2_role.app.permissions).access == "readwrite"
+    error_message = "The permission was not granted"
+  }
+}
+
+run "narrow" {
+  variables {
+    access = "read"
+  }
+
+  assert {
+    condition     = one(etcdv2_role.app.permissions).access == "read"
+    error_message = "The write permission was not revoked"
+  }
+}
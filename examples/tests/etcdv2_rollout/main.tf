@@ -0,0 +1,24 @@
+terraform {
+  required_providers {
+    etcdv2 = {
+      source = "github.com/retryW/etcdv2"
+    }
+  }
+}
+
+variable "host" {
+  type    = string
+  default = "http://127.0.0.1:4001"
+}
+
+provider "etcdv2" {
+  host = var.host
+}
+
+# Nothing confirms the rollout here, so it is promoted once the timeout passes
+resource "etcdv2_rollout" "config" {
+  key                = "/tests/rollout/config"
+  value              = "v1"
+  timeout            = 2
+  promote_on_timeout = true
+}
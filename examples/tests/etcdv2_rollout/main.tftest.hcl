@@ -0,0 +1,6 @@
+run "create" {
+  assert {
+    condition     = etcdv2_rollout.config.value == "v1"
+    error_message = "The value was not promoted"
+  }
+}
@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    etcdv2 = {
+      source = "github.com/retryW/etcdv2"
+    }
+  }
+}
+
+variable "host" {
+  type    = string
+  default = "http://127.0.0.1:4001"
+}
+
+provider "etcdv2" {
+  host = var.host
+}
+
+variable "password" {
+  type      = string
+  default   = "first"
+  sensitive = true
+}
+
+resource "etcdv2_role" "app" {
+  name = "tests-user-role"
+}
+
+resource "etcdv2_user" "app" {
+  name     = "tests-user"
+  password = var.password
+  roles    = [etcdv2_role.app.name]
+}
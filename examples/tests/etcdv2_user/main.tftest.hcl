@@ -0,0 +1,17 @@
+run "create" {
+  assert {
+    condition     = contains(etcdv2_user.app.roles, "tests-user-role")
+    error_message = "The role was not granted"
+  }
+}
+
+run "change_password" {
+  variables {
+    password = "second"
+  }
+
+  assert {
+    condition     = contains(etcdv2_user.app.roles, "tests-user-role")
+    error_message = "Changing the password should keep the roles"
+  }
+}
@@ -0,0 +1,315 @@
+package etcdv2mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// user is an auth user. The password is kept so tests can assert on it, but
+// is never returned by the API.
+type user struct {
+	name     string
+	password string
+	roles    map[string]bool
+}
+
+// Password returns the password of the user name, and whether it exists.
+func (s *Server) Password(name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[name]
+	if !ok {
+		return "", false
+	}
+
+	return u.password, true
+}
+
+// Role returns the role name, and whether it exists.
+func (s *Server) Role(name string) (clientv2.Role, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.roles[name]
+	if !ok {
+		return clientv2.Role{}, false
+	}
+
+	return *r, true
+}
+
+// UserRoles returns the sorted roles granted to the user name, and whether
+// the user exists.
+func (s *Server) UserRoles(name string) ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[name]
+	if !ok {
+		return nil, false
+	}
+
+	return sortedRoleNames(u.roles), true
+}
+
+// InjectAuthError makes the next request with method on the auth resource,
+// e.g. "roles/app", fail with status and message.
+func (s *Server) InjectAuthError(method, resource string, status int, message string) {
+	s.Inject(Fault{
+		Method: method,
+		Path:   authPrefix + "/" + resource,
+		Status: status,
+		Body:   AuthError{Message: message},
+		Times:  1,
+	})
+}
+
+func writeAuthError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, AuthError{Message: fmt.Sprintf(format, args...)})
+}
+
+func (s *Server) serveAuth(w http.ResponseWriter, r *http.Request, rest string) {
+	kind, name, _ := strings.Cut(rest, "/")
+
+	switch kind {
+	case "enable":
+		writeJSON(w, http.StatusOK, map[string]bool{"enabled": false})
+	case "roles":
+		if name == "" {
+			s.listRoles(w)
+			return
+		}
+		s.serveRole(w, r, name)
+	case "users":
+		if name == "" {
+			s.listUsers(w)
+			return
+		}
+		s.serveUser(w, r, name)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) listRoles(w http.ResponseWriter) {
+	roles := make([]clientv2.Role, 0, len(s.roles))
+	for _, name := range sortedRoleNames(s.roles) {
+		roles = append(roles, *s.roles[name])
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]clientv2.Role{"roles": roles})
+}
+
+func (s *Server) serveRole(w http.ResponseWriter, r *http.Request, name string) {
+	role, exists := s.roles[name]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeAuthError(w, http.StatusNotFound, "auth: Role %s does not exist.", name)
+			return
+		}
+		writeJSON(w, http.StatusOK, role)
+
+	case http.MethodDelete:
+		if !exists {
+			writeAuthError(w, http.StatusNotFound, "auth: Role %s does not exist.", name)
+			return
+		}
+		delete(s.roles, name)
+		w.WriteHeader(http.StatusOK)
+
+	case http.MethodPut:
+		var in clientv2.Role
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeAuthError(w, http.StatusBadRequest, "auth: invalid role: %s", err)
+			return
+		}
+
+		if in.Grant == nil && in.Revoke == nil {
+			if exists {
+				writeAuthError(w, http.StatusConflict, "auth: Role %s already exists.", name)
+				return
+			}
+			role = &clientv2.Role{Role: name}
+			role.Permissions.KV.Read = []string{}
+			role.Permissions.KV.Write = []string{}
+			s.roles[name] = role
+			writeJSON(w, http.StatusCreated, role)
+			return
+		}
+
+		if !exists {
+			writeAuthError(w, http.StatusNotFound, "auth: Role %s does not exist.", name)
+			return
+		}
+
+		updated := *role
+		if in.Revoke != nil {
+			var err error
+			if updated.Permissions.KV.Read, err = revoke(updated.Permissions.KV.Read, in.Revoke.KV.Read); err == nil {
+				updated.Permissions.KV.Write, err = revoke(updated.Permissions.KV.Write, in.Revoke.KV.Write)
+			}
+			if err != nil {
+				writeAuthError(w, http.StatusConflict, "auth: %s", err)
+				return
+			}
+		}
+		if in.Grant != nil {
+			updated.Permissions.KV.Read = grant(updated.Permissions.KV.Read, in.Grant.KV.Read)
+			updated.Permissions.KV.Write = grant(updated.Permissions.KV.Write, in.Grant.KV.Write)
+		}
+
+		*role = updated
+		writeJSON(w, http.StatusOK, role)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) listUsers(w http.ResponseWriter) {
+	names := make([]string, 0, len(s.users))
+	for name := range s.users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	users := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		users = append(users, s.exportUser(s.users[name]))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"users": users})
+}
+
+func (s *Server) serveUser(w http.ResponseWriter, r *http.Request, name string) {
+	u, exists := s.users[name]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeAuthError(w, http.StatusNotFound, "auth: User %s does not exist.", name)
+			return
+		}
+		writeJSON(w, http.StatusOK, s.exportUser(u))
+
+	case http.MethodDelete:
+		if !exists {
+			writeAuthError(w, http.StatusNotFound, "auth: User %s does not exist.", name)
+			return
+		}
+		delete(s.users, name)
+		w.WriteHeader(http.StatusOK)
+
+	case http.MethodPut:
+		var in clientv2.User
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeAuthError(w, http.StatusBadRequest, "auth: invalid user: %s", err)
+			return
+		}
+
+		// As in etcd, a PUT creates a missing user and updates an existing one
+		status := http.StatusOK
+		if !exists {
+			if len(in.Grant) > 0 || len(in.Revoke) > 0 {
+				writeAuthError(w, http.StatusNotFound, "auth: User %s does not exist.", name)
+				return
+			}
+			u = &user{name: name, roles: make(map[string]bool)}
+			status = http.StatusCreated
+		}
+
+		for _, role := range in.Grant {
+			if _, ok := s.roles[role]; !ok {
+				writeAuthError(w, http.StatusNotFound, "auth: Role %s does not exist.", role)
+				return
+			}
+			if u.roles[role] {
+				writeAuthError(w, http.StatusConflict, "auth: User %s already has role %s", name, role)
+				return
+			}
+		}
+		for _, role := range in.Revoke {
+			if !u.roles[role] {
+				writeAuthError(w, http.StatusConflict, "auth: User %s does not have role %s", name, role)
+				return
+			}
+		}
+
+		if in.Password != "" {
+			u.password = in.Password
+		}
+		for _, role := range in.Roles {
+			u.roles[role] = true
+		}
+		for _, role := range in.Grant {
+			u.roles[role] = true
+		}
+		for _, role := range in.Revoke {
+			delete(u.roles, role)
+		}
+
+		s.users[name] = u
+		writeJSON(w, status, s.exportUser(u))
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// exportUser returns the user as etcd reports it, with full role objects.
+func (s *Server) exportUser(u *user) map[string]interface{} {
+	roles := make([]clientv2.Role, 0, len(u.roles))
+	for _, name := range sortedRoleNames(u.roles) {
+		if role, ok := s.roles[name]; ok {
+			roles = append(roles, *role)
+		} else {
+			roles = append(roles, clientv2.Role{Role: name})
+		}
+	}
+
+	return map[string]interface{}{"user": u.name, "roles": roles}
+}
+
+// grant adds prefixes to perms, keeping them sorted and unique.
+func grant(perms, prefixes []string) []string {
+	set := make(map[string]bool, len(perms)+len(prefixes))
+	for _, p := range append(append([]string{}, perms...), prefixes...) {
+		set[p] = true
+	}
+
+	return sortedRoleNames(set)
+}
+
+// revoke removes prefixes from perms, failing if any is not granted.
+func revoke(perms, prefixes []string) ([]string, error) {
+	set := make(map[string]bool, len(perms))
+	for _, p := range perms {
+		set[p] = true
+	}
+	for _, p := range prefixes {
+		if !set[p] {
+			return nil, fmt.Errorf("permission %s is not granted", p)
+		}
+		delete(set, p)
+	}
+
+	return sortedRoleNames(set), nil
+}
+
+func sortedRoleNames[T any](set map[string]T) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
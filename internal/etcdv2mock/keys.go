@@ -0,0 +1,440 @@
+package etcdv2mock
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// node is a key or directory in the keyspace.
+type node struct {
+	key        string
+	value      string
+	dir        bool
+	created    uint64
+	modified   uint64
+	expiration *time.Time
+	children   map[string]*node
+}
+
+// statusForCode returns the HTTP status etcd answers an error code with.
+func statusForCode(code int) int {
+	switch code {
+	case clientv2.ErrorCodeKeyNotFound:
+		return http.StatusNotFound
+	case clientv2.ErrorCodeTestFailed, clientv2.ErrorCodeNodeExist:
+		return http.StatusPreconditionFailed
+	case clientv2.ErrorCodeNotFile, clientv2.ErrorCodeNotDir, clientv2.ErrorCodeRootROnly, clientv2.ErrorCodeDirNotEmpty:
+		return http.StatusForbidden
+	case clientv2.ErrorCodeUnauthorized:
+		return http.StatusUnauthorized
+	}
+
+	if code >= 300 {
+		return http.StatusInternalServerError
+	}
+
+	return http.StatusBadRequest
+}
+
+func (s *Server) keyError(code int, message, cause string) clientv2.Error {
+	return clientv2.Error{Code: code, Message: message, Cause: cause, Index: s.index}
+}
+
+func (s *Server) writeKeyError(w http.ResponseWriter, err clientv2.Error) {
+	writeJSON(w, statusForCode(err.Code), err)
+}
+
+func (s *Server) serveKeys(w http.ResponseWriter, r *http.Request, key string) {
+	key = path.Clean("/" + key)
+
+	if err := r.ParseForm(); err != nil {
+		s.writeKeyError(w, s.keyError(clientv2.ErrorCodeInvalidForm, "Invalid form", err.Error()))
+		return
+	}
+
+	var resp *clientv2.Response
+	var err *clientv2.Error
+	status := http.StatusOK
+
+	switch r.Method {
+	case http.MethodGet:
+		if r.Form.Get("wait") == "true" {
+			e := s.keyError(clientv2.ErrorCodeInvalidField, "Invalid field", "watches are not supported")
+			err = &e
+			break
+		}
+		resp, err = s.get(key, r.Form.Get("recursive") == "true")
+	case http.MethodPut:
+		resp, err = s.put(key, r.Form)
+		if resp != nil && resp.Action == "create" {
+			status = http.StatusCreated
+		}
+	case http.MethodPost:
+		resp, err = s.createInOrder(key, r.Form)
+		status = http.StatusCreated
+	case http.MethodDelete:
+		resp, err = s.delete(key, r.Form)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err != nil {
+		s.writeKeyError(w, *err)
+		return
+	}
+
+	w.Header().Set("X-Etcd-Index", strconv.FormatUint(s.index, 10))
+	writeJSON(w, status, resp)
+}
+
+// Value returns the value of key, and whether it exists and is not a
+// directory.
+func (s *Server) Value(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+
+	n := s.lookup(key)
+	if n == nil || n.dir {
+		return "", false
+	}
+
+	return n.value, true
+}
+
+// Set writes value to key as another client would, and returns the index it
+// was written at.
+func (s *Server) Set(key, value string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+
+	resp, err := s.put(key, map[string][]string{"value": {value}})
+	if err != nil {
+		panic(err)
+	}
+
+	return resp.Node.ModifiedIndex
+}
+
+// Delete removes key and everything below it as another client would, and
+// reports whether it existed.
+func (s *Server) Delete(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+
+	_, err := s.delete(key, map[string][]string{"recursive": {"true"}})
+
+	return err == nil
+}
+
+// lookup returns the node at key, or nil if there is none.
+func (s *Server) lookup(key string) *node {
+	n := s.root
+	for _, part := range splitKey(key) {
+		if !n.dir {
+			return nil
+		}
+		if n = n.children[part]; n == nil {
+			return nil
+		}
+	}
+
+	return n
+}
+
+// parentOf returns the directory holding key, creating any missing
+// directories on the way.
+func (s *Server) parentOf(key string) (*node, *clientv2.Error) {
+	n := s.root
+	for _, part := range splitKey(path.Dir(key)) {
+		child := n.children[part]
+		if child == nil {
+			child = &node{
+				key:      path.Join(n.key, part),
+				dir:      true,
+				created:  s.index,
+				modified: s.index,
+				children: make(map[string]*node),
+			}
+			n.children[part] = child
+		}
+		if !child.dir {
+			err := s.keyError(clientv2.ErrorCodeNotDir, "Not a directory", child.key)
+			return nil, &err
+		}
+		n = child
+	}
+
+	return n, nil
+}
+
+func (s *Server) get(key string, recursive bool) (*clientv2.Response, *clientv2.Error) {
+	n := s.lookup(key)
+	if n == nil {
+		err := s.keyError(clientv2.ErrorCodeKeyNotFound, "Key not found", key)
+		return nil, &err
+	}
+
+	return &clientv2.Response{Action: "get", Node: s.export(n, recursive, true)}, nil
+}
+
+func (s *Server) put(key string, form map[string][]string) (*clientv2.Response, *clientv2.Error) {
+	if key == "/" {
+		err := s.keyError(clientv2.ErrorCodeRootROnly, "Root is read only", "/")
+		return nil, &err
+	}
+
+	get := func(name string) string {
+		if v := form[name]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	dir := get("dir") == "true"
+	refresh := get("refresh") == "true"
+	prevExist := get("prevExist")
+	prevValue := get("prevValue")
+	prevIndex, _ := strconv.ParseUint(get("prevIndex"), 10, 64)
+
+	var expiration *time.Time
+	if ttl := get("ttl"); ttl != "" {
+		seconds, err := strconv.ParseInt(ttl, 10, 64)
+		if err != nil || seconds < 0 {
+			e := s.keyError(clientv2.ErrorCodeTTLNaN, "The given TTL in POST form is not a number", "Update")
+			return nil, &e
+		}
+		if seconds > 0 {
+			t := s.now().Add(time.Duration(seconds) * time.Second)
+			expiration = &t
+		}
+	}
+
+	existing := s.lookup(key)
+	action := "set"
+
+	switch {
+	case prevExist == "false":
+		if existing != nil {
+			err := s.keyError(clientv2.ErrorCodeNodeExist, "Key already exists", key)
+			return nil, &err
+		}
+		action = "create"
+	case prevExist == "true" || refresh:
+		if existing == nil {
+			err := s.keyError(clientv2.ErrorCodeKeyNotFound, "Key not found", key)
+			return nil, &err
+		}
+		action = "update"
+	}
+
+	if prevValue != "" || prevIndex != 0 {
+		if existing == nil {
+			err := s.keyError(clientv2.ErrorCodeKeyNotFound, "Key not found", key)
+			return nil, &err
+		}
+		if cause := compareFailure(existing, prevValue, prevIndex); cause != "" {
+			err := s.keyError(clientv2.ErrorCodeTestFailed, "Compare failed", cause)
+			return nil, &err
+		}
+		action = "compareAndSwap"
+	}
+
+	// A directory can only have its TTL updated
+	if existing != nil && existing.dir && ((!dir && !refresh) || action != "update") {
+		err := s.keyError(clientv2.ErrorCodeNotFile, "Not a file", key)
+		return nil, &err
+	}
+
+	parent, err := s.parentOf(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var prev *clientv2.Node
+	if existing != nil {
+		prev = s.export(existing, false, false)
+	}
+
+	s.index++
+	n := existing
+	if n == nil || (!refresh && n.dir != dir) {
+		n = &node{key: key, dir: dir, created: s.index}
+		if dir {
+			n.children = make(map[string]*node)
+		}
+		parent.children[path.Base(key)] = n
+	}
+	if !dir && !refresh {
+		n.value = get("value")
+	}
+	n.modified = s.index
+	n.expiration = expiration
+
+	return &clientv2.Response{Action: action, Node: s.export(n, false, false), PrevNode: prev}, nil
+}
+
+func (s *Server) createInOrder(dir string, form map[string][]string) (*clientv2.Response, *clientv2.Error) {
+	if d := s.lookup(dir); d != nil && !d.dir {
+		err := s.keyError(clientv2.ErrorCodeNotDir, "Not a directory", dir)
+		return nil, &err
+	}
+
+	key := path.Join(dir, fmt.Sprintf("%020d", s.index+1))
+	form["prevExist"] = []string{"false"}
+	delete(form, "dir")
+
+	return s.put(key, form)
+}
+
+func (s *Server) delete(key string, form map[string][]string) (*clientv2.Response, *clientv2.Error) {
+	if key == "/" {
+		err := s.keyError(clientv2.ErrorCodeRootROnly, "Root is read only", "/")
+		return nil, &err
+	}
+
+	get := func(name string) string {
+		if v := form[name]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	n := s.lookup(key)
+	if n == nil {
+		err := s.keyError(clientv2.ErrorCodeKeyNotFound, "Key not found", key)
+		return nil, &err
+	}
+
+	action := "delete"
+	prevValue := get("prevValue")
+	prevIndex, _ := strconv.ParseUint(get("prevIndex"), 10, 64)
+	if prevValue != "" || prevIndex != 0 {
+		if cause := compareFailure(n, prevValue, prevIndex); cause != "" {
+			err := s.keyError(clientv2.ErrorCodeTestFailed, "Compare failed", cause)
+			return nil, &err
+		}
+		action = "compareAndDelete"
+	}
+
+	recursive := get("recursive") == "true"
+	if n.dir {
+		if get("dir") != "true" && !recursive {
+			err := s.keyError(clientv2.ErrorCodeNotFile, "Not a file", key)
+			return nil, &err
+		}
+		if len(n.children) > 0 && !recursive {
+			err := s.keyError(clientv2.ErrorCodeDirNotEmpty, "Directory not empty", key)
+			return nil, &err
+		}
+	}
+
+	prev := s.export(n, false, false)
+
+	s.index++
+	delete(s.lookup(path.Dir(key)).children, path.Base(key))
+
+	return &clientv2.Response{
+		Action:   action,
+		Node:     &clientv2.Node{Key: key, Dir: n.dir, CreatedIndex: n.created, ModifiedIndex: s.index},
+		PrevNode: prev,
+	}, nil
+}
+
+// expire removes every node whose TTL has run out, advancing the index once
+// per node as etcd does.
+func (s *Server) expire() {
+	now := s.now()
+
+	var walk func(*node)
+	walk = func(dir *node) {
+		for _, name := range sortedNames(dir) {
+			child := dir.children[name]
+			if child.expiration != nil && !child.expiration.After(now) {
+				s.index++
+				delete(dir.children, name)
+				continue
+			}
+			if child.dir {
+				walk(child)
+			}
+		}
+	}
+	walk(s.root)
+}
+
+// export converts n to its API form. Directory children are included one
+// level deep when withChildren is set, or fully when recursive is set too.
+func (s *Server) export(n *node, recursive, withChildren bool) *clientv2.Node {
+	out := &clientv2.Node{
+		Key:           n.key,
+		Dir:           n.dir,
+		CreatedIndex:  n.created,
+		ModifiedIndex: n.modified,
+	}
+	if !n.dir {
+		out.Value = n.value
+	}
+	if n.expiration != nil {
+		expiration := *n.expiration
+		out.Expiration = &expiration
+		out.TTL = int64(expiration.Sub(s.now()).Round(time.Second) / time.Second)
+	}
+
+	if n.dir && withChildren {
+		for _, name := range sortedNames(n) {
+			// Hidden nodes are left out of listings, as etcd does
+			if strings.HasPrefix(name, "_") {
+				continue
+			}
+			out.Nodes = append(out.Nodes, s.export(n.children[name], recursive, recursive))
+		}
+	}
+
+	return out
+}
+
+// compareFailure returns the cause of a failed compare, or "" if n matches.
+func compareFailure(n *node, prevValue string, prevIndex uint64) string {
+	var causes []string
+	if prevValue != "" && prevValue != n.value {
+		causes = append(causes, fmt.Sprintf("[%s != %s]", prevValue, n.value))
+	}
+	if prevIndex != 0 && prevIndex != n.modified {
+		causes = append(causes, fmt.Sprintf("[%d != %d]", prevIndex, n.modified))
+	}
+
+	return strings.Join(causes, " ")
+}
+
+func sortedNames(dir *node) []string {
+	names := make([]string, 0, len(dir.children))
+	for name := range dir.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func splitKey(key string) []string {
+	key = strings.Trim(key, "/")
+	if key == "" || key == "." {
+		return nil
+	}
+
+	return strings.Split(key, "/")
+}
@@ -0,0 +1,240 @@
+// Package etcdv2mock implements an in-memory etcd v2 HTTP server covering the
+// keys, auth and members APIs used by the provider. Faults can be injected per
+// request so error paths can be exercised without a live cluster.
+package etcdv2mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+const (
+	keysPrefix    = "/v2/keys"
+	authPrefix    = "/v2/auth"
+	membersPrefix = "/v2/members"
+)
+
+// Fault is a canned response returned in place of handling a request.
+type Fault struct {
+	// Method matches the request method. Empty matches any method.
+	Method string
+
+	// Path matches requests whose URL path starts with it, e.g. "/v2/keys/app".
+	Path string
+
+	// Status is the HTTP status code of the response.
+	Status int
+
+	// Body is encoded as the JSON response body, e.g. a clientv2.Error for the
+	// keys API or an AuthError for the auth API.
+	Body interface{}
+
+	// Times is how many requests the fault answers. Zero answers every
+	// matching request until the faults are cleared.
+	Times int
+
+	// After is how many matching requests are handled as usual before the
+	// fault answers any, e.g. to fail the second of several grants.
+	After int
+}
+
+// AuthError is the body of an auth API error response.
+type AuthError struct {
+	Message string `json:"message"`
+}
+
+// Server is an in-memory etcd v2 server. The zero value is not usable; create
+// one with New and serve it with net/http or httptest.
+type Server struct {
+	mu sync.Mutex
+
+	index  uint64
+	root   *node
+	offset time.Duration
+
+	roles map[string]*clientv2.Role
+	users map[string]*user
+
+	members  []clientv2.Member
+	leaderID string
+
+	faults []*Fault
+}
+
+// New returns a server with an empty keyspace and a single member named
+// "default".
+func New() *Server {
+	return &Server{
+		root:  &node{key: "/", dir: true, children: make(map[string]*node)},
+		roles: make(map[string]*clientv2.Role),
+		users: make(map[string]*user),
+		members: []clientv2.Member{{
+			ID:       "8e9e05c52164694d",
+			Name:     "default",
+			PeerURLs: []string{"http://localhost:2380"},
+		}},
+		leaderID: "8e9e05c52164694d",
+	}
+}
+
+// Inject adds a fault. Faults are matched in the order they were added.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// InjectKeyError makes the next request with method on key fail with the
+// etcd error code.
+func (s *Server) InjectKeyError(method, key string, code int, message string) {
+	s.Inject(Fault{
+		Method: method,
+		Path:   keysPrefix + key,
+		Status: statusForCode(code),
+		Body:   clientv2.Error{Code: code, Message: message, Cause: key},
+		Times:  1,
+	})
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Advance moves the server clock forward, expiring any node whose TTL runs
+// out in the meantime.
+func (s *Server) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.offset += d
+}
+
+// Index returns the current etcd index.
+func (s *Server) Index() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.index
+}
+
+// SetMembers replaces the cluster members and makes leaderID the leader. An
+// empty leaderID leaves the cluster without a leader. Members without client
+// URLs are reported with the URL they were reached on.
+func (s *Server) SetMembers(members []clientv2.Member, leaderID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.members = members
+	s.leaderID = leaderID
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+
+	w.Header().Set("X-Etcd-Cluster-ID", "cdf818194e3a8c32")
+	w.Header().Set("X-Etcd-Index", strconv.FormatUint(s.index, 10))
+	w.Header().Set("X-Raft-Index", strconv.FormatUint(s.index, 10))
+	w.Header().Set("X-Raft-Term", "2")
+
+	if f := s.fault(r); f != nil {
+		writeJSON(w, f.Status, f.Body)
+		return
+	}
+
+	switch p := r.URL.Path; {
+	case p == keysPrefix || strings.HasPrefix(p, keysPrefix+"/"):
+		s.serveKeys(w, r, strings.TrimPrefix(p, keysPrefix))
+	case strings.HasPrefix(p, authPrefix+"/"):
+		s.serveAuth(w, r, strings.TrimPrefix(p, authPrefix+"/"))
+	case p == membersPrefix || strings.HasPrefix(p, membersPrefix+"/"):
+		s.serveMembers(w, r, strings.TrimPrefix(p, membersPrefix))
+	case p == "/version":
+		writeJSON(w, http.StatusOK, map[string]string{"etcdserver": "2.3.8", "etcdcluster": "2.3.0"})
+	case p == "/health":
+		writeJSON(w, http.StatusOK, map[string]string{"health": "true"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// fault returns the first injected fault matching r, consuming one of its
+// uses.
+func (s *Server) fault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		if f.After > 0 {
+			f.After--
+			continue
+		}
+
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+func (s *Server) serveMembers(w http.ResponseWriter, r *http.Request, rest string) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, AuthError{Message: "membership changes are not supported"})
+		return
+	}
+
+	members := make([]clientv2.Member, 0, len(s.members))
+	for _, m := range s.members {
+		if len(m.ClientURLs) == 0 {
+			m.ClientURLs = []string{"http://" + r.Host}
+		}
+		members = append(members, m)
+	}
+
+	switch rest {
+	case "", "/":
+		writeJSON(w, http.StatusOK, map[string][]clientv2.Member{"members": members})
+	case "/leader":
+		for _, m := range members {
+			if m.ID == s.leaderID {
+				writeJSON(w, http.StatusOK, m)
+				return
+			}
+		}
+		writeJSON(w, http.StatusServiceUnavailable, AuthError{Message: "etcdserver: no leader"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"terraform-provider-etcdv2/pkg/etcdv2ops"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &accessMatrixDataSource{}
+	_ datasource.DataSourceWithConfigure = &accessMatrixDataSource{}
+)
+
+var accessMatrixEntryAttrTypes = map[string]attr.Type{
+	"user":  types.StringType,
+	"role":  types.StringType,
+	"path":  types.StringType,
+	"read":  types.BoolType,
+	"write": types.BoolType,
+}
+
+func NewAccessMatrixDataSource() datasource.DataSource {
+	return &accessMatrixDataSource{}
+}
+
+type accessMatrixDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type accessMatrixDataSourceModel struct {
+	Entries types.List `tfsdk:"entries"`
+}
+
+// accessMatrixEntry is a single row of the access matrix.
+type accessMatrixEntry struct {
+	User  string
+	Role  string
+	Path  string
+	Read  bool
+	Write bool
+}
+
+func (d *accessMatrixDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_access_matrix"
+}
+
+func (d *accessMatrixDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Flattens every user, role and key permission of the cluster into a single list, " +
+			"for compliance reports generated from Terraform.",
+		Attributes: map[string]schema.Attribute{
+			"entries": schema.ListNestedAttribute{
+				MarkdownDescription: "One entry per user, role and path, sorted in that order with roles no user holds last",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"user": schema.StringAttribute{
+							MarkdownDescription: "The user name, or null for a role no user has been granted",
+							Computed:            true,
+						},
+						"role": schema.StringAttribute{
+							MarkdownDescription: "The role name",
+							Computed:            true,
+						},
+						"path": schema.StringAttribute{
+							MarkdownDescription: "The key or key pattern the role has permissions on",
+							Computed:            true,
+						},
+						"read": schema.BoolAttribute{
+							MarkdownDescription: "Whether the role can read the path",
+							Computed:            true,
+						},
+						"write": schema.BoolAttribute{
+							MarkdownDescription: "Whether the role can write the path",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *accessMatrixDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data accessMatrixDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := clientv2.New(d.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+
+		return
+	}
+
+	matrix, err := accessMatrix(ctx, clientv2.NewAuthUserAPI(client), clientv2.NewAuthRoleAPI(client))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd access matrix",
+			errorDetail(err),
+		)
+		return
+	}
+
+	entries := make([]attr.Value, 0, len(matrix))
+	for _, e := range matrix {
+		user := types.StringNull()
+		if e.User != "" {
+			user = types.StringValue(e.User)
+		}
+
+		entries = append(entries, types.ObjectValueMust(accessMatrixEntryAttrTypes, map[string]attr.Value{
+			"user":  user,
+			"role":  types.StringValue(e.Role),
+			"path":  types.StringValue(e.Path),
+			"read":  types.BoolValue(e.Read),
+			"write": types.BoolValue(e.Write),
+		}))
+	}
+
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: accessMatrixEntryAttrTypes}, entries)
+	resp.Diagnostics.Append(diags...)
+	data.Entries = list
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *accessMatrixDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
+
+// accessMatrix lists the permissions of every role, once for each user holding
+// it. Roles held by no user are listed with an empty user.
+func accessMatrix(ctx context.Context, uApi clientv2.AuthUserAPI, rApi clientv2.AuthRoleAPI) ([]accessMatrixEntry, error) {
+	roleNames, err := rApi.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Permissions of each role, by path
+	paths := make(map[string][]accessMatrixEntry, len(roleNames))
+	for _, name := range roleNames {
+		role, err := rApi.GetRole(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		paths[name] = rolePaths(name, role.Permissions)
+	}
+
+	userNames, err := uApi.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(userNames)
+
+	var matrix []accessMatrixEntry
+	held := make(map[string]bool)
+
+	for _, name := range userNames {
+		user, err := etcdv2ops.GetUser(ctx, uApi, name)
+		if err != nil {
+			return nil, err
+		}
+
+		roles := append([]string{}, user.Roles...)
+		sort.Strings(roles)
+
+		for _, role := range roles {
+			held[role] = true
+			for _, e := range paths[role] {
+				e.User = name
+				matrix = append(matrix, e)
+			}
+		}
+	}
+
+	sort.Strings(roleNames)
+	for _, role := range roleNames {
+		if !held[role] {
+			matrix = append(matrix, paths[role]...)
+		}
+	}
+
+	return matrix, nil
+}
+
+// rolePaths flattens the permissions of a role into one entry per path.
+func rolePaths(role string, perms clientv2.Permissions) []accessMatrixEntry {
+	byPath := make(map[string]*accessMatrixEntry)
+	entry := func(path string) *accessMatrixEntry {
+		if e, ok := byPath[path]; ok {
+			return e
+		}
+		e := &accessMatrixEntry{Role: role, Path: path}
+		byPath[path] = e
+		return e
+	}
+
+	for _, path := range perms.KV.Read {
+		entry(path).Read = true
+	}
+	for _, path := range perms.KV.Write {
+		entry(path).Write = true
+	}
+
+	entries := make([]accessMatrixEntry, 0, len(byPath))
+	for _, e := range byPath {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+
+	return entries
+}
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// defaultLeaderElectionGrace is used when leader_election_grace is not configured.
+	defaultLeaderElectionGrace = 10 * time.Second
+
+	leaderElectionInitialBackoff = 250 * time.Millisecond
+	leaderElectionMaxBackoff     = 2 * time.Second
+)
+
+// keysAPI builds a KeysAPI from the provider configuration. The returned API
+// rides out leader elections rather than failing on the first attempt.
+func (p *etcdv2ProviderData) keysAPI() (clientv2.KeysAPI, error) {
+	return p.keysAPIWith(p.clientConfig())
+}
+
+// recordingKeysAPI is keysAPI, additionally returning a recorder holding the
+// headers of the last response.
+func (p *etcdv2ProviderData) recordingKeysAPI() (clientv2.KeysAPI, *headerRecorder, error) {
+	cfg := p.clientConfig()
+	recorder := &headerRecorder{CancelableTransport: cfg.Transport}
+	cfg.Transport = recorder
+
+	kApi, err := p.keysAPIWith(cfg)
+	return kApi, recorder, err
+}
+
+func (p *etcdv2ProviderData) keysAPIWith(cfg clientv2.Config) (clientv2.KeysAPI, error) {
+	client, err := clientv2.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &leaderElectionKeysAPI{
+		KeysAPI:   clientv2.NewKeysAPI(client),
+		grace:     p.leaderElectionGrace,
+		telemetry: p.telemetry,
+	}, nil
+}
+
+// leaderElectionKeysAPI retries requests that fail because the cluster has no
+// leader, backing off exponentially for up to grace. Elections take several
+// seconds, far longer than a request is normally worth retrying for.
+type leaderElectionKeysAPI struct {
+	clientv2.KeysAPI
+
+	grace time.Duration
+
+	// telemetry is nil unless telemetry is configured.
+	telemetry *telemetry
+}
+
+func (k *leaderElectionKeysAPI) Get(ctx context.Context, key string, opts *clientv2.GetOptions) (*clientv2.Response, error) {
+	return k.retry(ctx, func() (*clientv2.Response, error) {
+		return k.KeysAPI.Get(ctx, key, opts)
+	})
+}
+
+func (k *leaderElectionKeysAPI) Set(ctx context.Context, key, value string, opts *clientv2.SetOptions) (*clientv2.Response, error) {
+	return k.retry(ctx, func() (*clientv2.Response, error) {
+		return k.KeysAPI.Set(ctx, key, value, opts)
+	})
+}
+
+func (k *leaderElectionKeysAPI) Delete(ctx context.Context, key string, opts *clientv2.DeleteOptions) (*clientv2.Response, error) {
+	return k.retry(ctx, func() (*clientv2.Response, error) {
+		return k.KeysAPI.Delete(ctx, key, opts)
+	})
+}
+
+func (k *leaderElectionKeysAPI) Create(ctx context.Context, key, value string) (*clientv2.Response, error) {
+	return k.retry(ctx, func() (*clientv2.Response, error) {
+		return k.KeysAPI.Create(ctx, key, value)
+	})
+}
+
+func (k *leaderElectionKeysAPI) CreateInOrder(ctx context.Context, dir, value string, opts *clientv2.CreateInOrderOptions) (*clientv2.Response, error) {
+	return k.retry(ctx, func() (*clientv2.Response, error) {
+		return k.KeysAPI.CreateInOrder(ctx, dir, value, opts)
+	})
+}
+
+func (k *leaderElectionKeysAPI) Update(ctx context.Context, key, value string) (*clientv2.Response, error) {
+	return k.retry(ctx, func() (*clientv2.Response, error) {
+		return k.KeysAPI.Update(ctx, key, value)
+	})
+}
+
+func (k *leaderElectionKeysAPI) retry(ctx context.Context, fn func() (*clientv2.Response, error)) (*clientv2.Response, error) {
+	deadline := time.Now().Add(k.grace)
+	backoff := leaderElectionInitialBackoff
+
+	for {
+		resp, err := fn()
+		if err == nil || !isLeaderElection(err) || time.Now().Add(backoff).After(deadline) {
+			return resp, err
+		}
+
+		if k.telemetry != nil {
+			k.telemetry.count("leader_election_retries")
+		}
+
+		tflog.Debug(ctx, "etcd cluster has no leader, backing off", map[string]interface{}{
+			"backoff": backoff.String(),
+			"error":   err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > leaderElectionMaxBackoff {
+			backoff = leaderElectionMaxBackoff
+		}
+	}
+}
+
+// isLeaderElection reports whether err means the request was refused because
+// the cluster is electing a leader, in which case it was not applied.
+func isLeaderElection(err error) bool {
+	switch e := err.(type) {
+	case clientv2.Error:
+		return e.Code == clientv2.ErrorCodeLeaderElect
+	case *clientv2.ClusterError:
+		for _, memberErr := range e.Errors {
+			if !strings.HasSuffix(memberErr.Error(), "has no leader") {
+				return false
+			}
+		}
+		return len(e.Errors) > 0
+	}
+
+	return false
+}
+
+// leaderClient builds a client that sends requests to the cluster leader
+// first. The auth API has no quorum reads, so a follower can answer with a
+// permission list that does not yet include a grant made moments earlier.
+// The configured endpoints stay as a fallback, and are used alone when the
+// leader cannot be found or the endpoints sit behind a path prefix, where
+// member URLs are not reachable.
+func (p *etcdv2ProviderData) leaderClient(ctx context.Context) (clientv2.Client, error) {
+	client, err := clientv2.New(p.clientConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, endpoint := range p.cfg.Endpoints {
+		if u, err := url.Parse(endpoint); err != nil || strings.Trim(u.Path, "/") != "" {
+			return client, nil
+		}
+	}
+
+	leader, err := clientv2.NewMembersAPI(client).Leader(ctx)
+	if err != nil || leader == nil || len(leader.ClientURLs) == 0 {
+		tflog.Debug(ctx, "etcd leader not found, reading from configured endpoints", map[string]interface{}{
+			"error": fmt.Sprint(err),
+		})
+		return client, nil
+	}
+
+	cfg := *p.cfg
+	cfg.Endpoints = p.endpoints.filter(append(append([]string{}, leader.ClientURLs...), p.cfg.Endpoints...))
+
+	return clientv2.New(cfg)
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// roleAccess describes the access a single role has to a key.
+type roleAccess struct {
+	Role  string
+	Read  bool
+	Write bool
+}
+
+// permissionMatches reports whether an etcd v2 permission pattern covers key.
+// Patterns ending in '*' match by prefix, anything else must match exactly.
+func permissionMatches(pattern, key string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(key, strings.TrimSuffix(pattern, "*"))
+	}
+
+	return pattern == key
+}
+
+func anyPermissionMatches(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if permissionMatches(pattern, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rolesWithAccess cross-references every role's permissions against key and
+// returns the roles that can read or write it, in the order etcd lists them.
+func rolesWithAccess(ctx context.Context, rApi clientv2.AuthRoleAPI, key string) ([]roleAccess, error) {
+	names, err := rApi.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var access []roleAccess
+	for _, name := range names {
+		role, err := rApi.GetRole(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := roleAccess{
+			Role:  name,
+			Read:  anyPermissionMatches(role.Permissions.KV.Read, key),
+			Write: anyPermissionMatches(role.Permissions.KV.Write, key),
+		}
+		if entry.Read || entry.Write {
+			access = append(access, entry)
+		}
+	}
+
+	return access, nil
+}
+
+const (
+	accessRead      = "read"
+	accessWrite     = "write"
+	accessReadWrite = "readwrite"
+)
+
+// isAuthExist reports whether err is etcd refusing to add a role or user that
+// already exists. The auth API only reports this in the error message.
+func isAuthExist(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "already exists")
+}
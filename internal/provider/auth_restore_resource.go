@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource              = &AuthRestoreResource{}
+	_ resource.ResourceWithConfigure = &AuthRestoreResource{}
+)
+
+func NewAuthRestoreResource() resource.Resource {
+	return &AuthRestoreResource{}
+}
+
+// AuthRestoreResource defines the resource implementation
+type AuthRestoreResource struct {
+	provider *etcdv2ProviderData
+}
+
+// AuthRestoreResourceModel describes the resource data model.
+type AuthRestoreResourceModel struct {
+	Snapshot  types.String `tfsdk:"snapshot"`
+	Passwords types.Map    `tfsdk:"passwords"`
+	Roles     types.List   `tfsdk:"roles"`
+	Users     types.List   `tfsdk:"users"`
+}
+
+func (r *AuthRestoreResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_auth_restore"
+}
+
+func (r *AuthRestoreResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Applies a snapshot taken by `etcdv2_auth_snapshot`, creating missing roles and users and " +
+			"bringing the permissions and roles of existing ones in line with it. Roles and users not in the snapshot are left alone, " +
+			"and destroying the resource leaves the restored configuration in place.",
+		Attributes: map[string]schema.Attribute{
+			"snapshot": schema.StringAttribute{
+				MarkdownDescription: "The JSON document produced by `etcdv2_auth_snapshot`. Changing it applies the new snapshot",
+				Required:            true,
+			},
+			"passwords": schema.MapAttribute{
+				MarkdownDescription: "Passwords keyed by user name, for users of the snapshot that do not exist yet and must be created. " +
+					"The passwords of existing users are not changed",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"roles": schema.ListAttribute{
+				MarkdownDescription: "The names of the roles restored",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"users": schema.ListAttribute{
+				MarkdownDescription: "The names of the users restored",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *AuthRestoreResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	r.provider = provider
+}
+
+func (r *AuthRestoreResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AuthRestoreResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.restore(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AuthRestoreResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The snapshot is applied once per change, so there is nothing to refresh
+}
+
+func (r *AuthRestoreResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data AuthRestoreResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.restore(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AuthRestoreResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The restored roles and users are left in place
+}
+
+// restore applies the snapshot of the model and records what it restored.
+func (r *AuthRestoreResource) restore(ctx context.Context, data *AuthRestoreResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var snapshot authSnapshot
+	if err := json.Unmarshal([]byte(data.Snapshot.ValueString()), &snapshot); err != nil {
+		diags.AddAttributeError(
+			path.Root("snapshot"),
+			"Invalid etcd auth snapshot",
+			"The snapshot is not a JSON document produced by etcdv2_auth_snapshot: "+err.Error(),
+		)
+		return diags
+	}
+
+	passwords := map[string]string{}
+	diags.Append(data.Passwords.ElementsAs(ctx, &passwords, false)...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		diags.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return diags
+	}
+
+	// Create new etcd client from config
+	client, err := clientv2.New(r.provider.clientConfig())
+	if err != nil {
+		diags.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return diags
+	}
+
+	if err := restoreAuthSnapshot(ctx, clientv2.NewAuthUserAPI(client), clientv2.NewAuthRoleAPI(client), &snapshot, passwords); err != nil {
+		diags.AddError(
+			"Unable to Restore etcd auth snapshot",
+			errorDetail(err),
+		)
+		return diags
+	}
+
+	roles := []string{}
+	for _, role := range snapshot.Roles {
+		if role.Name != rootRole {
+			roles = append(roles, role.Name)
+		}
+	}
+	users := []string{}
+	for _, user := range snapshot.Users {
+		users = append(users, user.Name)
+	}
+
+	var d diag.Diagnostics
+	data.Roles, d = types.ListValueFrom(ctx, types.StringType, roles)
+	diags.Append(d...)
+	data.Users, d = types.ListValueFrom(ctx, types.StringType, users)
+	diags.Append(d...)
+
+	return diags
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &authSnapshotDataSource{}
+	_ datasource.DataSourceWithConfigure = &authSnapshotDataSource{}
+)
+
+func NewAuthSnapshotDataSource() datasource.DataSource {
+	return &authSnapshotDataSource{}
+}
+
+type authSnapshotDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type authSnapshotDataSourceModel struct {
+	JSON types.String `tfsdk:"json"`
+}
+
+func (d *authSnapshotDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_auth_snapshot"
+}
+
+func (d *authSnapshotDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Dumps every role with its permissions and every user with their roles as JSON, " +
+			"to be applied to another cluster or restored later with `etcdv2_auth_restore`. etcd never returns passwords, so they are not included.",
+		Attributes: map[string]schema.Attribute{
+			"json": schema.StringAttribute{
+				MarkdownDescription: "The snapshot as a JSON document",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *authSnapshotDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data authSnapshotDataSourceModel
+
+	client, err := clientv2.New(d.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	snapshot, err := readAuthSnapshot(ctx, clientv2.NewAuthUserAPI(client), clientv2.NewAuthRoleAPI(client))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd auth configuration",
+			errorDetail(err),
+		)
+		return
+	}
+
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to encode etcd auth snapshot",
+			err.Error(),
+		)
+		return
+	}
+	data.JSON = types.StringValue(string(encoded))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *authSnapshotDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"terraform-provider-etcdv2/pkg/etcdv2ops"
+)
+
+// rootRole is built into etcd and cannot be changed.
+const rootRole = "root"
+
+// authSnapshot is the JSON document etcdv2_auth_snapshot produces and
+// etcdv2_auth_restore applies. etcd never returns passwords, so users are
+// recorded by their roles only.
+type authSnapshot struct {
+	Roles []snapshotRole `json:"roles"`
+	Users []snapshotUser `json:"users"`
+}
+
+type snapshotRole struct {
+	Name  string   `json:"name"`
+	Read  []string `json:"read"`
+	Write []string `json:"write"`
+}
+
+type snapshotUser struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+// readAuthSnapshot reads every role and user, sorted by name so the snapshot
+// only changes when the auth configuration does.
+func readAuthSnapshot(ctx context.Context, uApi clientv2.AuthUserAPI, rApi clientv2.AuthRoleAPI) (*authSnapshot, error) {
+	snapshot := &authSnapshot{Roles: []snapshotRole{}, Users: []snapshotUser{}}
+
+	roleNames, err := rApi.ListRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing roles: %w", err)
+	}
+	sort.Strings(roleNames)
+
+	for _, name := range roleNames {
+		role, err := rApi.GetRole(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("reading role %s: %w", name, err)
+		}
+
+		read := append([]string{}, role.Permissions.KV.Read...)
+		write := append([]string{}, role.Permissions.KV.Write...)
+		sort.Strings(read)
+		sort.Strings(write)
+
+		snapshot.Roles = append(snapshot.Roles, snapshotRole{Name: name, Read: read, Write: write})
+	}
+
+	userNames, err := uApi.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	sort.Strings(userNames)
+
+	for _, name := range userNames {
+		user, err := etcdv2ops.GetUser(ctx, uApi, name)
+		if err != nil {
+			return nil, fmt.Errorf("reading user %s: %w", name, err)
+		}
+
+		roles := append([]string{}, user.Roles...)
+		sort.Strings(roles)
+
+		snapshot.Users = append(snapshot.Users, snapshotUser{Name: name, Roles: roles})
+	}
+
+	return snapshot, nil
+}
+
+// restoreAuthSnapshot creates the roles and users of snapshot that are missing
+// and brings the permissions and roles of existing ones in line with it.
+// Users that do not exist yet are created with their password in passwords.
+// Roles and users that are not in the snapshot are left alone.
+func restoreAuthSnapshot(ctx context.Context, uApi clientv2.AuthUserAPI, rApi clientv2.AuthRoleAPI, snapshot *authSnapshot, passwords map[string]string) error {
+	for _, role := range snapshot.Roles {
+		if role.Name == rootRole {
+			continue
+		}
+
+		var have clientv2.Permissions
+		existing, err := rApi.GetRole(ctx, role.Name)
+		switch {
+		case clientv2.IsRoleNotFound(err):
+			if err := rApi.AddRole(ctx, role.Name); err != nil {
+				return fmt.Errorf("creating role %s: %w", role.Name, err)
+			}
+		case err != nil:
+			return fmt.Errorf("reading role %s: %w", role.Name, err)
+		default:
+			have = existing.Permissions
+		}
+
+		var want clientv2.Permissions
+		want.KV.Read, want.KV.Write = role.Read, role.Write
+
+		if err := etcdv2ops.SyncRole(ctx, rApi, role.Name, want, have); err != nil {
+			return fmt.Errorf("restoring permissions of role %s: %w", role.Name, err)
+		}
+	}
+
+	for _, user := range snapshot.Users {
+		var have []string
+		existing, err := etcdv2ops.GetUser(ctx, uApi, user.Name)
+		switch {
+		case clientv2.IsUserNotFound(err):
+			password, ok := passwords[user.Name]
+			if !ok {
+				return fmt.Errorf("user %s does not exist and no password is set for it", user.Name)
+			}
+			if err := uApi.AddUser(ctx, user.Name, password); err != nil {
+				return fmt.Errorf("creating user %s: %w", user.Name, err)
+			}
+		case err != nil:
+			return fmt.Errorf("reading user %s: %w", user.Name, err)
+		default:
+			have = existing.Roles
+		}
+
+		if err := etcdv2ops.SyncUser(ctx, uApi, etcdv2ops.User{Name: user.Name, Roles: user.Roles}, etcdv2ops.User{Name: user.Name, Roles: have}); err != nil {
+			return fmt.Errorf("restoring roles of user %s: %w", user.Name, err)
+		}
+	}
+
+	return nil
+}
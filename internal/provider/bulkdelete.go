@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// defaultDeleteBatchSize is used when delete_batch_size is not configured.
+	defaultDeleteBatchSize = 500
+
+	bulkDeleteMaxAttempts    = 5
+	bulkDeleteInitialBackoff = 500 * time.Millisecond
+	bulkDeleteMaxBackoff     = 10 * time.Second
+)
+
+// deletePrefix deletes everything beneath prefix and the prefix itself. A
+// single recursive delete of a large subtree can outlast the request timeout,
+// so the nodes are deleted one by one, children before their directory, with
+// progress logged after every batchSize nodes. Each delete is retried with
+// back-off when it fails without an answer from etcd.
+func deletePrefix(ctx context.Context, kApi clientv2.KeysAPI, prefix string, batchSize int) error {
+	tree, err := kApi.Get(ctx, prefix, &clientv2.GetOptions{Recursive: true})
+	if clientv2.IsKeyNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var nodes clientv2.Nodes
+	var walk func(clientv2.Nodes)
+	walk = func(children clientv2.Nodes) {
+		for _, n := range children {
+			walk(n.Nodes)
+			nodes = append(nodes, n)
+		}
+	}
+	walk(tree.Node.Nodes)
+
+	for i, n := range nodes {
+		opts := &clientv2.DeleteOptions{Dir: n.Dir}
+		err := retryDelete(ctx, func() error {
+			_, err := kApi.Delete(ctx, n.Key, opts)
+			return err
+		})
+		// Keys written since the listing are left to the final delete
+		if err != nil && !clientv2.IsKeyNotFound(err) && !isDirNotEmpty(err) {
+			return err
+		}
+
+		if deleted := i + 1; deleted%batchSize == 0 || deleted == len(nodes) {
+			tflog.Info(ctx, "Deleting etcd prefix", map[string]interface{}{
+				"prefix":  prefix,
+				"deleted": deleted,
+				"total":   len(nodes),
+			})
+		}
+	}
+
+	err = retryDelete(ctx, func() error {
+		_, err := kApi.Delete(ctx, prefix, &clientv2.DeleteOptions{Dir: true, Recursive: true})
+		return err
+	})
+	if clientv2.IsKeyNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// retryDelete calls fn until it succeeds, etcd answers with an error or the
+// attempts run out. Errors etcd answered with are final, others such as
+// timeouts and unreachable members are retried.
+func retryDelete(ctx context.Context, fn func() error) error {
+	backoff := bulkDeleteInitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if _, answered := err.(clientv2.Error); err == nil || answered || attempt == bulkDeleteMaxAttempts {
+			return err
+		}
+
+		tflog.Debug(ctx, "etcd delete failed, backing off", map[string]interface{}{
+			"attempt": attempt,
+			"backoff": backoff.String(),
+			"error":   err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > bulkDeleteMaxBackoff {
+			backoff = bulkDeleteMaxBackoff
+		}
+	}
+}
+
+// isDirNotEmpty reports whether err means a directory could not be deleted
+// because it still has children.
+func isDirNotEmpty(err error) bool {
+	clientErr, ok := err.(clientv2.Error)
+	return ok && clientErr.Code == clientv2.ErrorCodeDirNotEmpty
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Operations recorded in the change log.
+const (
+	changeCreate = "create"
+	changeUpdate = "update"
+	changeDelete = "delete"
+)
+
+// changeLog appends a record of every write made during an apply to an etcd
+// in-order directory, giving the cluster its own audit trail of changes.
+type changeLog struct {
+	dir       string
+	workspace string
+}
+
+// changeRecord is the JSON stored for each change.
+type changeRecord struct {
+	Operation string    `json:"operation"`
+	Key       string    `json:"key"`
+	OldIndex  uint64    `json:"old_index,omitempty"`
+	NewIndex  uint64    `json:"new_index,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Workspace string    `json:"workspace"`
+}
+
+// newChangeLog records changes under dir. The workspace is taken from
+// fencing_workspace when set, otherwise from TF_WORKSPACE.
+func newChangeLog(dir, workspace string) *changeLog {
+	return &changeLog{dir: dir, workspace: changeWorkspace(workspace)}
+}
+
+// changeWorkspace returns the workspace changes are attributed to, workspace
+// when set, otherwise TF_WORKSPACE.
+func changeWorkspace(workspace string) string {
+	if workspace == "" {
+		workspace = os.Getenv("TF_WORKSPACE")
+	}
+	if workspace == "" {
+		workspace = "default"
+	}
+
+	return workspace
+}
+
+// recordChange appends a change to the change log when change_log_key is set,
+// and stamps its last writer when last_writer_key is set. The write has
+// already happened, so a failure to record it is a warning.
+func (p *etcdv2ProviderData) recordChange(ctx context.Context, operation, key string, oldIndex, newIndex uint64) diag.Diagnostics {
+	diags := p.stampLastWriter(ctx, operation, key, newIndex)
+
+	if p.changeLog == nil {
+		return diags
+	}
+
+	if err := p.changeLog.append(ctx, p, changeRecord{
+		Operation: operation,
+		Key:       key,
+		OldIndex:  oldIndex,
+		NewIndex:  newIndex,
+		Timestamp: time.Now().UTC(),
+		Workspace: p.changeLog.workspace,
+	}); err != nil {
+		diags.AddWarning(
+			"Unable to record change in etcd change log",
+			"The "+operation+" of "+key+" was applied but could not be appended to "+p.changeLog.dir+".\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+	}
+
+	return diags
+}
+
+func (l *changeLog) append(ctx context.Context, p *etcdv2ProviderData, record changeRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	kApi, err := p.keysAPI()
+	if err != nil {
+		return err
+	}
+
+	_, err = kApi.CreateInOrder(ctx, l.dir, string(value), nil)
+	return err
+}
+
+// prevIndex returns the modified index of the node a write replaced, or 0 if
+// it replaced nothing.
+func prevIndex(prev *clientv2.Node) uint64 {
+	if prev == nil {
+		return 0
+	}
+	return prev.ModifiedIndex
+}
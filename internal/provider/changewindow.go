@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// changeWindow is a cron expression matching the minutes in which resources
+// with respect_change_window may be changed.
+type changeWindow struct {
+	spec     string
+	location *time.Location
+
+	minute, hour, dom, month, dow uint64
+
+	// Like cron, a day matches either field when both are restricted
+	domAny, dowAny bool
+}
+
+var (
+	cronMonthNames = []string{"", "JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	cronDayNames   = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+)
+
+// parseChangeWindow parses a five field cron expression of minute, hour, day
+// of month, month and day of week, evaluated in timezone. Fields accept *,
+// values, ranges, lists and steps, and month and day names.
+func parseChangeWindow(spec, timezone string) (*changeWindow, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w", timezone, err)
+	}
+
+	w := &changeWindow{
+		spec:     spec,
+		location: location,
+		domAny:   fields[2] == "*",
+		dowAny:   fields[4] == "*",
+	}
+
+	for _, f := range []struct {
+		name     string
+		field    string
+		min, max int
+		names    []string
+		bits     *uint64
+	}{
+		{"minute", fields[0], 0, 59, nil, &w.minute},
+		{"hour", fields[1], 0, 23, nil, &w.hour},
+		{"day of month", fields[2], 1, 31, nil, &w.dom},
+		{"month", fields[3], 1, 12, cronMonthNames, &w.month},
+		{"day of week", fields[4], 0, 7, cronDayNames, &w.dow},
+	} {
+		bits, err := parseCronField(f.field, f.min, f.max, f.names)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		*f.bits = bits
+	}
+
+	// Sunday can be given as 0 or 7
+	if w.dow&(1<<7) != 0 {
+		w.dow |= 1
+	}
+
+	return w, nil
+}
+
+// parseCronField returns a bit set of the values a cron field matches.
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			part = part[:i]
+		}
+
+		low, high := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+
+			var err error
+			if low, err = parseCronValue(bounds[0], names); err != nil {
+				return 0, err
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = parseCronValue(bounds[1], names); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				high = max
+			}
+		}
+
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(value string, names []string) (int, error) {
+	for i, name := range names {
+		if name != "" && strings.EqualFold(value, name) {
+			return i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+
+	return v, nil
+}
+
+// contains reports whether the minute of t is within the window.
+func (w *changeWindow) contains(t time.Time) bool {
+	t = t.In(w.location)
+
+	if w.minute&(1<<uint(t.Minute())) == 0 || w.hour&(1<<uint(t.Hour())) == 0 || w.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	domMatch := w.dom&(1<<uint(t.Day())) != 0
+	dowMatch := w.dow&(1<<uint(t.Weekday())) != 0
+
+	if w.domAny || w.dowAny {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+// checkChangeWindow fails when respect is set and the current time is outside
+// change_window_cron.
+func (p *etcdv2ProviderData) checkChangeWindow(respect types.Bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !respect.ValueBool() {
+		return diags
+	}
+
+	if p.changeWindow == nil {
+		diags.AddAttributeError(
+			path.Root("respect_change_window"),
+			"No etcd change window configured",
+			"respect_change_window is set, but the provider has no change_window_cron to respect.",
+		)
+		return diags
+	}
+
+	if now := time.Now(); !p.changeWindow.contains(now) {
+		diags.AddError(
+			"Outside of etcd change window",
+			fmt.Sprintf("Changes are only allowed within %q (%s), and it is %s.",
+				p.changeWindow.spec, p.changeWindow.location, now.In(p.changeWindow.location).Format(time.RFC3339)),
+		)
+	}
+
+	return diags
+}
@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+// randomTime returns a time within a few centuries of 2000 from seconds.
+func randomTime(seconds int64) time.Time {
+	return time.Unix(946684800+seconds%(1<<33), 0).UTC()
+}
+
+func TestChangeWindow_any(t *testing.T) {
+	w, err := parseChangeWindow("* * * * *", "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := func(seconds int64) bool {
+		return w.contains(randomTime(seconds))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChangeWindow_minute(t *testing.T) {
+	f := func(seconds int64) bool {
+		at := randomTime(seconds)
+
+		w, err := parseChangeWindow(fmt.Sprintf("%d %d %d %d *", at.Minute(), at.Hour(), at.Day(), at.Month()), "UTC")
+		if err != nil {
+			return false
+		}
+
+		return w.contains(at) && !w.contains(at.Add(time.Minute)) && !w.contains(at.Add(-time.Minute))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChangeWindow_names(t *testing.T) {
+	f := func(seconds int64) bool {
+		at := randomTime(seconds)
+
+		numeric, err := parseChangeWindow(fmt.Sprintf("* * * %d %d", at.Month(), at.Weekday()), "UTC")
+		if err != nil {
+			return false
+		}
+		named, err := parseChangeWindow(fmt.Sprintf("* * * %s %s", cronMonthNames[at.Month()], cronDayNames[at.Weekday()]), "UTC")
+		if err != nil {
+			return false
+		}
+
+		return numeric.contains(at) && named.contains(at) &&
+			numeric.month == named.month && numeric.dow == named.dow
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChangeWindow_sunday(t *testing.T) {
+	zero, err := parseChangeWindow("* * * * 0", "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seven, err := parseChangeWindow("* * * * 7", "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := func(seconds int64) bool {
+		at := randomTime(seconds)
+		sunday := at.Weekday() == time.Sunday
+
+		return zero.contains(at) == sunday && seven.contains(at) == sunday
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChangeWindow_timezone(t *testing.T) {
+	f := func(seconds int64) bool {
+		at := randomTime(seconds)
+
+		// The window is evaluated in its own timezone, whatever the zone of t
+		w, err := parseChangeWindow(fmt.Sprintf("%d %d * * *", at.Minute(), at.Hour()), "UTC")
+		if err != nil {
+			return false
+		}
+
+		return w.contains(at.In(time.FixedZone("", 5*3600))) && w.contains(at.In(time.FixedZone("", -8*3600)))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
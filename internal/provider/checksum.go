@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// valueChecksums returns the hex encoded SHA-256 and MD5 digests of value, or
+// nulls when value is null.
+func valueChecksums(value types.String) (sha256sum, md5sum types.String) {
+	if value.IsNull() || value.IsUnknown() {
+		return types.StringNull(), types.StringNull()
+	}
+
+	s := sha256.Sum256([]byte(value.ValueString()))
+	m := md5.Sum([]byte(value.ValueString()))
+
+	return types.StringValue(hex.EncodeToString(s[:])), types.StringValue(hex.EncodeToString(m[:]))
+}
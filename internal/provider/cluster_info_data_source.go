@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &clusterInfoDataSource{}
+	_ datasource.DataSourceWithConfigure = &clusterInfoDataSource{}
+)
+
+func NewClusterInfoDataSource() datasource.DataSource {
+	return &clusterInfoDataSource{}
+}
+
+type clusterInfoDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type clusterInfoDataSourceModel struct {
+	ClusterID   types.String `tfsdk:"cluster_id"`
+	LeaderID    types.String `tfsdk:"leader_id"`
+	LeaderName  types.String `tfsdk:"leader_name"`
+	RaftTerm    types.Int64  `tfsdk:"raft_term"`
+	RaftIndex   types.Int64  `tfsdk:"raft_index"`
+	MemberCount types.Int64  `tfsdk:"member_count"`
+}
+
+func (d *clusterInfoDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_info"
+}
+
+func (d *clusterInfoDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reports the identity, leadership and size of the etcd cluster.",
+		Attributes: map[string]schema.Attribute{
+			"cluster_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the cluster",
+				Computed:            true,
+			},
+			"leader_id": schema.StringAttribute{
+				MarkdownDescription: "The member ID of the current leader",
+				Computed:            true,
+			},
+			"leader_name": schema.StringAttribute{
+				MarkdownDescription: "The member name of the current leader",
+				Computed:            true,
+			},
+			"raft_term": schema.Int64Attribute{
+				MarkdownDescription: "The current raft term",
+				Computed:            true,
+			},
+			"raft_index": schema.Int64Attribute{
+				MarkdownDescription: "The current raft index",
+				Computed:            true,
+			},
+			"member_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of members in the cluster",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *clusterInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data clusterInfoDataSourceModel
+
+	client, err := clientv2.New(d.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+
+		return
+	}
+
+	headers, err := readClusterHeaders(ctx, client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd cluster state",
+			errorDetail(err),
+		)
+		return
+	}
+
+	mApi := clientv2.NewMembersAPI(client)
+
+	members, err := mApi.List(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to List etcd members",
+			errorDetail(err),
+		)
+		return
+	}
+
+	leader, err := mApi.Leader(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd leader",
+			errorDetail(err),
+		)
+		return
+	}
+
+	data.ClusterID = types.StringValue(headers.ClusterID)
+	data.LeaderID = types.StringValue(leader.ID)
+	data.LeaderName = types.StringValue(leader.Name)
+	data.RaftTerm = types.Int64Value(int64(headers.RaftTerm))
+	data.RaftIndex = types.Int64Value(int64(headers.RaftIndex))
+	data.MemberCount = types.Int64Value(int64(len(members)))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *clusterInfoDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
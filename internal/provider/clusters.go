@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// withEndpoint returns a copy of the provider data that talks to endpoint
+// instead of the configured host, for a cluster of endpoints_by_name. State
+// tied to a single cluster is not shared with the copy.
+func (p *etcdv2ProviderData) withEndpoint(name, endpoint string) *etcdv2ProviderData {
+	cfg := *p.cfg
+	cfg.Endpoints = []string{endpoint}
+
+	cluster := *p
+	cluster.cfg = &cfg
+	cluster.clusterAlias = name
+	cluster.missing = &missingKeys{}
+	cluster.claims = &keyClaims{}
+	cluster.clusters = nil
+
+	if p.fence != nil {
+		cluster.fence = &applyFence{key: p.fence.key, ttl: p.fence.ttl, owner: p.fence.owner, renewal: newHeartbeats()}
+	}
+	if p.writeAccess != nil {
+		cluster.writeAccess = &writeAccess{tokenAuth: p.writeAccess.tokenAuth}
+	}
+	if p.snapshot != nil {
+		cluster.snapshot = newSnapshotIndex(p.snapshot.tolerance)
+	}
+
+	return &cluster
+}
+
+// forCluster returns the provider data for the cluster named by the cluster
+// attribute, or p itself when it is not set.
+func (p *etcdv2ProviderData) forCluster(name types.String) (*etcdv2ProviderData, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if name.ValueString() == "" {
+		return p, diags
+	}
+
+	cluster, ok := p.clusters[name.ValueString()]
+	if !ok {
+		names := make([]string, 0, len(p.clusters))
+		for n := range p.clusters {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		diags.AddAttributeError(
+			path.Root("cluster"),
+			"Unknown etcd cluster",
+			"The cluster "+name.ValueString()+" is not in the provider's endpoints_by_name. Known clusters: "+strings.Join(names, ", "),
+		)
+		return nil, diags
+	}
+
+	return cluster, diags
+}
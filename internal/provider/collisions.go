@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+// keyClaims records which resource plans to write each key. Every resource
+// is planned once per provider process, so a key claimed twice is written by
+// two resources and the last writer would silently win on every apply.
+type keyClaims struct {
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+// claim records owner for key and returns the owner of an earlier claim, if
+// another resource already claimed it. A resource being replaced is planned
+// twice, so a repeated claim by the same owner is not a conflict.
+func (c *keyClaims) claim(key, owner string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.owners == nil {
+		c.owners = make(map[string]string)
+	}
+
+	key = "/" + strings.Trim(key, "/")
+	if previous, ok := c.owners[key]; ok && previous != owner {
+		return previous, true
+	}
+
+	c.owners[key] = owner
+	return "", false
+}
+
+// claimOwner describes the resource planning config from state as the owner
+// of its keys. Providers are not told resource addresses, so owners are
+// described by the resource type, the attribute locating the keys and a
+// fingerprint of the configuration and prior state, which tells apart
+// resources sharing a key. The prior state tells a copy of a block from the
+// resource it was copied from, but two new resources configured identically
+// still share a fingerprint and are not reported.
+func claimOwner(typeName, locator string, config tfsdk.Config, state tfsdk.State) string {
+	sum := sha256.Sum256([]byte(config.Raw.String() + "\x00" + state.Raw.String()))
+
+	return fmt.Sprintf("%s with %s (configuration %x)", typeName, locator, sum[:4])
+}
+
+// claimKey fails if key is already claimed by another resource in this plan.
+func (p *etcdv2ProviderData) claimKey(attribute path.Path, key, owner string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if previous, ok := p.claims.claim(key, owner); ok {
+		diags.AddAttributeError(
+			attribute,
+			"Conflicting etcd key",
+			fmt.Sprintf("The key %s is written by both this resource, %s, and %s. Only one resource may manage a key, "+
+				"otherwise the last writer wins and state flaps between applies.\n\n"+
+				"Two new resources with identical configurations cannot be told apart, so they are not reported.", key, owner, previous),
+		)
+	}
+
+	return diags
+}
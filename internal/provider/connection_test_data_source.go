@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &connectionTestDataSource{}
+	_ datasource.DataSourceWithConfigure = &connectionTestDataSource{}
+)
+
+const (
+	// defaultConnectionTestKey is used when scratch_key is not configured.
+	defaultConnectionTestKey = "/terraform/connection-test"
+
+	// connectionTestTTL expires the scratch key should the cleanup fail.
+	connectionTestTTL = 60 * time.Second
+)
+
+func NewConnectionTestDataSource() datasource.DataSource {
+	return &connectionTestDataSource{}
+}
+
+type connectionTestDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type connectionTestDataSourceModel struct {
+	ReadKey     types.String `tfsdk:"read_key"`
+	ScratchKey  types.String `tfsdk:"scratch_key"`
+	SkipWrite   types.Bool   `tfsdk:"skip_write"`
+	Read        types.Bool   `tfsdk:"read"`
+	Write       types.Bool   `tfsdk:"write"`
+	Auth        types.Bool   `tfsdk:"auth"`
+	AuthEnabled types.Bool   `tfsdk:"auth_enabled"`
+	Passed      types.Bool   `tfsdk:"passed"`
+	Errors      types.List   `tfsdk:"errors"`
+}
+
+func (d *connectionTestDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_connection_test"
+}
+
+func (d *connectionTestDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Checks that the provider configuration can read, write and query auth, reporting each check separately " +
+			"instead of failing, so pipelines can pre-flight the configuration with a `check` block or postcondition before a long apply.",
+		Attributes: map[string]schema.Attribute{
+			"read_key": schema.StringAttribute{
+				MarkdownDescription: "The key the read check reads. A key that does not exist passes the check. Defaults to `/`",
+				Optional:            true,
+			},
+			"scratch_key": schema.StringAttribute{
+				MarkdownDescription: "The key the write check writes with a short TTL and deletes again. Defaults to `" + defaultConnectionTestKey + "`",
+				Optional:            true,
+			},
+			"skip_write": schema.BoolAttribute{
+				MarkdownDescription: "Skip the write check, leaving `write` null, e.g. for read-only credentials",
+				Optional:            true,
+			},
+			"read": schema.BoolAttribute{
+				MarkdownDescription: "Whether `read_key` could be read",
+				Computed:            true,
+			},
+			"write": schema.BoolAttribute{
+				MarkdownDescription: "Whether `scratch_key` could be written and deleted",
+				Computed:            true,
+			},
+			"auth": schema.BoolAttribute{
+				MarkdownDescription: "Whether the auth status could be read and, with auth enabled, the cluster accepted the configured credentials",
+				Computed:            true,
+			},
+			"auth_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the cluster has auth enabled, or null if it could not be determined",
+				Computed:            true,
+			},
+			"passed": schema.BoolAttribute{
+				MarkdownDescription: "Whether every check that ran passed",
+				Computed:            true,
+			},
+			"errors": schema.ListAttribute{
+				MarkdownDescription: "The reasons the failed checks failed",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *connectionTestDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data connectionTestDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readKey := "/"
+	if !data.ReadKey.IsNull() {
+		readKey = data.ReadKey.ValueString()
+	}
+
+	scratchKey := defaultConnectionTestKey
+	if !data.ScratchKey.IsNull() {
+		scratchKey = data.ScratchKey.ValueString()
+	}
+
+	resp.Diagnostics.Append(d.provider.checkKeyPolicy("read_key", types.StringValue(readKey))...)
+	resp.Diagnostics.Append(d.provider.checkKeyPolicy("scratch_key", types.StringValue(scratchKey))...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := clientv2.New(d.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+
+		return
+	}
+
+	kApi, err := d.provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+
+		return
+	}
+
+	errs := []string{}
+	fail := func(check string, err error) types.Bool {
+		errs = append(errs, check+": "+err.Error())
+		return types.BoolValue(false)
+	}
+
+	data.Read = types.BoolValue(true)
+	if _, err := kApi.Get(ctx, readKey, nil); err != nil && !clientv2.IsKeyNotFound(err) {
+		data.Read = fail("read", err)
+	}
+
+	data.Write = types.BoolNull()
+	if !data.SkipWrite.ValueBool() {
+		data.Write = types.BoolValue(true)
+		value := fmt.Sprintf("written by terraform at %s", time.Now().UTC().Format(time.RFC3339))
+		if _, err := kApi.Set(ctx, scratchKey, value, &clientv2.SetOptions{TTL: connectionTestTTL}); err != nil {
+			data.Write = fail("write", err)
+		} else if _, err := kApi.Delete(ctx, scratchKey, nil); err != nil && !clientv2.IsKeyNotFound(err) {
+			data.Write = fail("write", fmt.Errorf("the scratch key was written but could not be deleted: %w", err))
+		}
+	}
+
+	data.Auth, data.AuthEnabled = types.BoolValue(true), types.BoolNull()
+	if enabled, err := readAuthEnabled(ctx, client); err != nil {
+		data.Auth = fail("auth", err)
+	} else {
+		data.AuthEnabled = types.BoolValue(enabled)
+
+		if enabled {
+			// etcd answers 401 both for rejected credentials and for
+			// credentials without access to the root
+			status, err := rawStatus(ctx, client, "/v2/keys/")
+			switch {
+			case err != nil:
+				data.Auth = fail("auth", err)
+			case status == http.StatusUnauthorized:
+				data.Auth = fail("auth", fmt.Errorf("the cluster refused the configured credentials"))
+			}
+		}
+	}
+
+	data.Passed = types.BoolValue(len(errs) == 0)
+
+	list, diags := types.ListValueFrom(ctx, types.StringType, errs)
+	resp.Diagnostics.Append(diags...)
+	data.Errors = list
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *connectionTestDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
+
+// readAuthEnabled reads whether auth is enabled from /v2/auth/enable, which
+// answers without credentials.
+func readAuthEnabled(ctx context.Context, client clientv2.Client) (bool, error) {
+	resp, body, err := client.Do(ctx, &getPathAction{path: "/v2/auth/enable"})
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %s reading the auth status", resp.Status)
+	}
+
+	var status struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return false, fmt.Errorf("unable to parse the auth status: %w", err)
+	}
+
+	return status.Enabled, nil
+}
+
+// rawStatus returns the status code of a GET of path.
+func rawStatus(ctx context.Context, client clientv2.Client, path string) (int, error) {
+	resp, _, err := client.Do(ctx, &getPathAction{path: path})
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+const (
+	// verifyWriteAttempts is how many quorum reads are made before a write is
+	// considered lost.
+	verifyWriteAttempts = 5
+
+	// verifyWriteInterval is the pause between quorum reads.
+	verifyWriteInterval = 200 * time.Millisecond
+)
+
+// verifyWrite re-reads written.Key with a quorum Get and checks the cluster
+// reflects the write that produced written. A proxy or lagging member can
+// acknowledge a write that never reaches the cluster, so this catches it at
+// apply time rather than on the next plan.
+func verifyWrite(ctx context.Context, kApi clientv2.KeysAPI, written *clientv2.Node) error {
+	var lastErr error
+
+	for attempt := 0; attempt < verifyWriteAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(verifyWriteInterval):
+			}
+		}
+
+		resp, err := kApi.Get(ctx, written.Key, &clientv2.GetOptions{Quorum: true})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		node := resp.Node
+		switch {
+		case node.ModifiedIndex > written.ModifiedIndex:
+			// Superseded by a later write, but ours was not dropped.
+			return nil
+		case node.ModifiedIndex == written.ModifiedIndex && node.Value == written.Value:
+			return nil
+		case node.ModifiedIndex == written.ModifiedIndex:
+			return fmt.Errorf("key %q at index %d holds an unexpected value", written.Key, written.ModifiedIndex)
+		}
+
+		lastErr = fmt.Errorf("key %q is at index %d, expected at least %d", written.Key, node.ModifiedIndex, written.ModifiedIndex)
+	}
+
+	return fmt.Errorf("write was not observed after %d quorum reads: %w", verifyWriteAttempts, lastErr)
+}
+
+// requireIndex fails unless the cluster has applied index, so a write made
+// after it is causally ordered behind the write that produced index. The
+// index only grows, so checking it once before writing is enough.
+func requireIndex(ctx context.Context, kApi clientv2.KeysAPI, index uint64) error {
+	resp, err := kApi.Get(ctx, "/", &clientv2.GetOptions{Quorum: true})
+	if err != nil {
+		return err
+	}
+
+	if resp.Index < index {
+		return fmt.Errorf("cluster is at index %d, expected at least %d", resp.Index, index)
+	}
+
+	return nil
+}
+
+// requireParent fails unless the directory holding key already exists, so
+// writes never create intermediate directories implicitly. Keys at the root
+// always have a parent.
+func requireParent(ctx context.Context, kApi clientv2.KeysAPI, key string) error {
+	parent := path.Dir(path.Join("/", key))
+	if parent == "/" {
+		return nil
+	}
+
+	resp, err := kApi.Get(ctx, parent, nil)
+	if clientv2.IsKeyNotFound(err) {
+		return fmt.Errorf("parent directory %s does not exist", parent)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !resp.Node.Dir {
+		return fmt.Errorf("parent %s is not a directory", parent)
+	}
+
+	return nil
+}
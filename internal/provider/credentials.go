@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// keyCredentials is the JSON document read by credentials_from_key,
+// credentials_file and credentials_exec.
+type keyCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// credentialsFromKey reads basic auth credentials stored as JSON at key using
+// the bootstrap configuration cfg.
+func credentialsFromKey(ctx context.Context, cfg clientv2.Config, key string) (*keyCredentials, error) {
+	client, err := clientv2.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	kApi := clientv2.NewKeysAPI(client)
+
+	resp, err := kApi.Get(ctx, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCredentials([]byte(resp.Node.Value), "value of "+key)
+}
+
+// checkCredentialsRequired reads the root of the keyspace without credentials
+// and fails if etcd answers 401, meaning auth is enabled and the guest role
+// cannot read keys. Otherwise every resource would fail with its own
+// insufficient credentials error. Clusters that cannot be reached are left
+// for the first request to report.
+func checkCredentialsRequired(ctx context.Context, cfg clientv2.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client, err := clientv2.New(cfg)
+	if err != nil {
+		return diags
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, versionCheckTimeout)
+	defer cancel()
+
+	resp, _, err := client.Do(ctx, &getPathAction{path: "/v2/keys/"})
+	if err != nil {
+		tflog.Debug(ctx, "Unable to check whether etcd requires credentials", map[string]interface{}{"error": err.Error()})
+		return diags
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		diags.AddError(
+			"etcd requires credentials",
+			"The cluster has auth enabled and refused a request made without credentials. "+
+				"Set username and password, or bearer_token or auth_header for gateways accepting tokens. "+
+				"Credentials can also be read from the environment, credentials_file, credentials_exec or credentials_from_key.",
+		)
+	}
+
+	return diags
+}
+
+// credentialsFromFile reads a JSON credentials document from path.
+func credentialsFromFile(path string) (*keyCredentials, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCredentials(raw, path)
+}
+
+// credentialsFromExec runs a helper command and reads a JSON credentials
+// document from its standard output.
+func credentialsFromExec(ctx context.Context, command []string) (*keyCredentials, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("credentials_exec must name a command")
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credentials helper %s failed: %w: %s", command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return parseCredentials(out, "the output of "+command[0])
+}
+
+// parseCredentials decodes a JSON credentials document read from source.
+func parseCredentials(raw []byte, source string) (*keyCredentials, error) {
+	var creds keyCredentials
+	if err := json.Unmarshal(raw, &creds); err != nil {
+		return nil, fmt.Errorf("%s is not a JSON credentials document: %w", source, err)
+	}
+
+	if creds.Username == "" || creds.Password == "" {
+		return nil, fmt.Errorf("%s must set both username and password", source)
+	}
+
+	return &creds, nil
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Encodings values can be decoded from on read.
+const (
+	decodeNone       = "none"
+	decodeAuto       = "auto"
+	decodeBase64     = "base64"
+	decodeGzipBase64 = "gzip+base64"
+)
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decodeValue decodes a value stored encoded by tools such as confd or chef.
+// With auto, base64 is only decoded if it yields gzip data or UTF-8 text, and
+// values that are not base64 are returned as they are.
+func decodeValue(encoding, value string) (string, error) {
+	switch encoding {
+	case "", decodeNone:
+		return value, nil
+	case decodeBase64:
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", fmt.Errorf("value is not base64: %w", err)
+		}
+		return string(decoded), nil
+	case decodeGzipBase64:
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", fmt.Errorf("value is not base64: %w", err)
+		}
+		return gunzip(decoded)
+	case decodeAuto:
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || value == "" {
+			return value, nil
+		}
+		if bytes.HasPrefix(decoded, gzipMagic) {
+			return gunzip(decoded)
+		}
+		if !utf8.Valid(decoded) {
+			return value, nil
+		}
+		return string(decoded), nil
+	default:
+		return "", fmt.Errorf("unknown decode %q, expected one of none, auto, base64 or gzip+base64", encoding)
+	}
+}
+
+func gunzip(data []byte) (string, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("value is not gzip compressed: %w", err)
+	}
+	defer r.Close()
+
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("decompressing value: %w", err)
+	}
+
+	return string(decompressed), nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+)
+
+func TestDecodeValue_none(t *testing.T) {
+	f := func(value string) bool {
+		decoded, err := decodeValue(decodeNone, value)
+		return err == nil && decoded == value
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecodeValue_base64(t *testing.T) {
+	f := func(data []byte) bool {
+		decoded, err := decodeValue(decodeBase64, base64.StdEncoding.EncodeToString(data))
+		return err == nil && decoded == string(data)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecodeValue_gzipBase64(t *testing.T) {
+	f := func(value string, level uint8) bool {
+		encoded := gzipBase64(t, value, int(level)%(gzip.BestCompression+1))
+
+		for _, encoding := range []string{decodeGzipBase64, decodeAuto} {
+			if decoded, err := decodeValue(encoding, encoded); err != nil || decoded != value {
+				return false
+			}
+		}
+
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecodeValue_auto(t *testing.T) {
+	f := func(data []byte) bool {
+		encoded := base64.StdEncoding.EncodeToString(data)
+
+		decoded, err := decodeValue(decodeAuto, encoded)
+		if err != nil {
+			return false
+		}
+
+		// Text is decoded, anything else is left encoded
+		if len(data) > 0 && utf8.Valid(data) && !bytes.HasPrefix(data, gzipMagic) {
+			return decoded == string(data)
+		}
+		if !bytes.HasPrefix(data, gzipMagic) {
+			return decoded == encoded
+		}
+
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecodeValue_autoNotBase64(t *testing.T) {
+	f := func(value string) bool {
+		// Values with characters outside the base64 alphabet are returned as is
+		value += "!"
+
+		decoded, err := decodeValue(decodeAuto, value)
+		return err == nil && decoded == value
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecodeValue_huge(t *testing.T) {
+	value := strings.Repeat("etcd v2 ☃ ", 1<<17)
+
+	decoded, err := decodeValue(decodeGzipBase64, gzipBase64(t, value, gzip.DefaultCompression))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != value {
+		t.Errorf("decoded %d bytes, want %d", len(decoded), len(value))
+	}
+}
+
+func gzipBase64(t *testing.T, value string, level int) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(value)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
@@ -0,0 +1,268 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &directoryDataSource{}
+	_ datasource.DataSourceWithConfigure = &directoryDataSource{}
+)
+
+var directoryNodeAttrTypes = map[string]attr.Type{
+	"key":   types.StringType,
+	"name":  types.StringType,
+	"dir":   types.BoolType,
+	"value": types.StringType,
+}
+
+func NewDirectoryDataSource() datasource.DataSource {
+	return &directoryDataSource{}
+}
+
+type directoryDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type directoryDataSourceModel struct {
+	Key       types.String `tfsdk:"key"`
+	Recursive types.Bool   `tfsdk:"recursive"`
+	KeysOnly  types.Bool   `tfsdk:"keys_only"`
+	Nodes     types.List   `tfsdk:"nodes"`
+	Decode    types.String `tfsdk:"decode"`
+
+	IncludeHidden types.Bool `tfsdk:"include_hidden"`
+	HiddenNames   types.List `tfsdk:"hidden_names"`
+
+	Cluster types.String `tfsdk:"cluster"`
+}
+
+func (d *directoryDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_directory"
+}
+
+func (d *directoryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the nodes beneath an etcd directory, sorted by key.",
+		Attributes: map[string]schema.Attribute{
+			"cluster": schema.StringAttribute{
+				MarkdownDescription: "The name of a cluster in the provider's `endpoints_by_name` to read from instead of `host`",
+				Optional:            true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The directory to list (e.g. '/foo')",
+				Required:            true,
+			},
+			"recursive": schema.BoolAttribute{
+				MarkdownDescription: "List every node in the subtree. When false only the immediate children are returned. Defaults to true",
+				Optional:            true,
+			},
+			"keys_only": schema.BoolAttribute{
+				MarkdownDescription: "Leave `value` null for every node, so large or secret values are not stored in state",
+				Optional:            true,
+			},
+			"decode": schema.StringAttribute{
+				MarkdownDescription: "Decode the values of nodes stored encoded by other tools, one of `none`, `base64`, `gzip+base64` or `auto`. " +
+					"`auto` decodes base64 values that hold gzip data or UTF-8 text and leaves other values as they are. Defaults to `none`",
+				Optional: true,
+			},
+			"include_hidden": schema.BoolAttribute{
+				MarkdownDescription: "Also return nodes whose names start with an underscore, which etcd leaves out of listings. " +
+					"They can only be read by name, so each directory is checked for the `_meta` and `_<name>_history` " +
+					"directories this provider creates and for `hidden_names`",
+				Optional: true,
+			},
+			"hidden_names": schema.ListAttribute{
+				MarkdownDescription: "Further hidden names to check for in each directory when `include_hidden` is set, e.g. `_state`",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"nodes": schema.ListNestedAttribute{
+				MarkdownDescription: "The nodes found beneath the directory",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							MarkdownDescription: "The full key of the node",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The last path segment of the key",
+							Computed:            true,
+						},
+						"dir": schema.BoolAttribute{
+							MarkdownDescription: "Whether the node is a directory",
+							Computed:            true,
+						},
+						"value": schema.StringAttribute{
+							MarkdownDescription: "The value of the node, empty for directories and null with `keys_only`",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *directoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data directoryDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := d.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(provider.checkKeyPolicy("key", data.Key)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	kApi, err := provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+
+		return
+	}
+
+	recursive := data.Recursive.IsNull() || data.Recursive.ValueBool()
+
+	if err := provider.missing.lookup(data.Key.ValueString()); err != nil {
+		resp.Diagnostics.Append(provider.missing.Diagnostic(data.Key.ValueString()))
+		return
+	}
+
+	directory, err := kApi.Get(context.Background(), data.Key.ValueString(), &clientv2.GetOptions{
+		Recursive: recursive,
+		Sort:      true,
+	})
+	if err != nil {
+		if provider.missing.record(data.Key.ValueString(), err) {
+			resp.Diagnostics.Append(provider.missing.Diagnostic(data.Key.ValueString()))
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd directory",
+			errorDetail(err),
+		)
+		return
+	}
+
+	if provider.snapshot != nil {
+		if err := provider.snapshot.check(directory); err != nil {
+			resp.Diagnostics.AddError(
+				"Inconsistent etcd snapshot",
+				"The directory was read too far past the index seen by the first read of this run: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if !directory.Node.Dir {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd directory",
+			"The key "+data.Key.ValueString()+" exists but is not a directory",
+		)
+		return
+	}
+
+	if data.IncludeHidden.ValueBool() {
+		var names []string
+		resp.Diagnostics.Append(data.HiddenNames.ElementsAs(ctx, &names, false)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if err := revealHidden(ctx, kApi, directory.Node, names, recursive); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read hidden etcd nodes",
+				errorDetail(err),
+			)
+			return
+		}
+	}
+
+	keysOnly := data.KeysOnly.ValueBool()
+
+	var nodes []attr.Value
+	var decodeErr error
+	var sensitiveKey string
+	var walk func(clientv2.Nodes)
+	walk = func(children clientv2.Nodes) {
+		for _, n := range children {
+			value := types.StringValue(n.Value)
+			if keysOnly {
+				value = types.StringNull()
+			} else if !n.Dir {
+				if sensitiveKey == "" && provider.isSensitiveKey(n.Key) {
+					sensitiveKey = n.Key
+				}
+				decoded, err := decodeValue(data.Decode.ValueString(), n.Value)
+				if err != nil && decodeErr == nil {
+					decodeErr = fmt.Errorf("%s: %w", n.Key, err)
+				}
+				value = types.StringValue(decoded)
+			}
+
+			nodes = append(nodes, types.ObjectValueMust(directoryNodeAttrTypes, map[string]attr.Value{
+				"key":   types.StringValue(n.Key),
+				"name":  types.StringValue(path.Base(n.Key)),
+				"dir":   types.BoolValue(n.Dir),
+				"value": value,
+			}))
+			walk(n.Nodes)
+		}
+	}
+	walk(directory.Node.Nodes)
+
+	if sensitiveKey != "" {
+		resp.Diagnostics.Append(sensitiveValueError("keys_only", sensitiveKey))
+		return
+	}
+
+	if decodeErr != nil {
+		resp.Diagnostics.AddError(
+			"Unable to decode etcd directory",
+			"A value could not be decoded: "+decodeErr.Error(),
+		)
+		return
+	}
+
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: directoryNodeAttrTypes}, nodes)
+	resp.Diagnostics.Append(diags...)
+	data.Nodes = list
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *directoryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
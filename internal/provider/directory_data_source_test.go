@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-etcdv2/internal/testetcd"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDirectoryDataSource_sensitive(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	etcd.Set("/app/config/log_level", "info")
+	etcd.Set("/app/secrets/db/password", "hunter2")
+
+	provider := etcd.ProviderConfig(`sensitive_key_prefixes = ["/app/secrets"]`)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccDirectoryDataSourceConfig("/app", false),
+				// The error names the key, never the value
+				ExpectError: regexp.MustCompile(`(?s)Sensitive etcd value.*/app/secrets/db/password`),
+			},
+			// Keys alone expose no values
+			{
+				Config: provider + testAccDirectoryDataSourceConfig("/app", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.etcdv2_directory.test", "nodes.#", "5"),
+					resource.TestCheckNoResourceAttr("data.etcdv2_directory.test", "nodes.4.value"),
+				),
+			},
+			{
+				Config: provider + testAccDirectoryDataSourceConfig("/app/config", false),
+				Check:  resource.TestCheckResourceAttr("data.etcdv2_directory.test", "nodes.0.value", "info"),
+			},
+		},
+	})
+}
+
+func testAccDirectoryDataSourceConfig(key string, keysOnly bool) string {
+	return fmt.Sprintf(`
+data "etcdv2_directory" "test" {
+  key       = %[1]q
+  keys_only = %[2]t
+}
+`, key, keysOnly)
+}
@@ -0,0 +1,445 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource               = &DirectoryResource{}
+	_ resource.ResourceWithConfigure  = &DirectoryResource{}
+	_ resource.ResourceWithModifyPlan = &DirectoryResource{}
+)
+
+func NewDirectoryResource() resource.Resource {
+	return &DirectoryResource{}
+}
+
+// DirectoryResource defines the resource implementation
+type DirectoryResource struct {
+	provider *etcdv2ProviderData
+}
+
+// DirectoryResourceModel describes the resource data model.
+type DirectoryResourceModel struct {
+	Key            types.String `tfsdk:"key"`
+	TTL            types.Int64  `tfsdk:"ttl"`
+	RefreshOnApply types.Bool   `tfsdk:"refresh_on_apply"`
+	ModifiedIndex  types.Int64  `tfsdk:"modified_index"`
+	Expiration     types.String `tfsdk:"expiration"`
+	MaxChildren    types.Int64  `tfsdk:"max_children"`
+
+	RequireQuorumMembers types.Int64 `tfsdk:"require_quorum_members"`
+
+	Cluster types.String `tfsdk:"cluster"`
+
+	RespectChangeWindow types.Bool `tfsdk:"respect_change_window"`
+}
+
+func (r *DirectoryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_directory"
+}
+
+func (r *DirectoryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "etcdv2 Directory resource. A directory with a `ttl` expires together with everything beneath it." +
+			" Destroying a directory that still has children fails. Planning fails if another resource writes the same key," +
+			" except for two new resources configured identically, which cannot be told apart.",
+		Attributes: map[string]schema.Attribute{
+			"cluster": schema.StringAttribute{
+				MarkdownDescription: "The name of a cluster in the provider's `endpoints_by_name` to manage the directory in instead of `host`. Changing it recreates the resource",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"respect_change_window": schema.BoolAttribute{
+				MarkdownDescription: "Fail to create, update or delete the directory outside of the provider's `change_window_cron`",
+				Optional:            true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The unique location of this directory (e.g. '/foo/bar')",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ttl": schema.Int64Attribute{
+				MarkdownDescription: "The time to live of this directory in seconds",
+				Optional:            true,
+			},
+			"refresh_on_apply": schema.BoolAttribute{
+				MarkdownDescription: "Reset the TTL of this directory on every apply",
+				Optional:            true,
+			},
+			"max_children": schema.Int64Attribute{
+				MarkdownDescription: "Fail the apply if the directory holds more than this many direct children",
+				Optional:            true,
+			},
+			"modified_index": schema.Int64Attribute{
+				MarkdownDescription: "The index at which this directory was last modified",
+				Computed:            true,
+			},
+			"expiration": schema.StringAttribute{
+				MarkdownDescription: "The RFC3339 time at which this directory expires, if it has a TTL",
+				Computed:            true,
+			},
+			"require_quorum_members": schema.Int64Attribute{
+				MarkdownDescription: "Refuse updates and deletes unless at least this many cluster members report healthy, " +
+					"so the directory is never modified while the cluster is degraded",
+				Optional: true,
+			},
+		},
+	}
+}
+
+func (r *DirectoryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	r.provider = provider
+}
+
+func (r *DirectoryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(r.provider.checkPlannedKeys(ctx, req.Plan, "key")...)
+
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan DirectoryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(plan.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Key.IsUnknown() {
+		resp.Diagnostics.Append(provider.claimKey(path.Root("key"), plan.Key.ValueString(), claimOwner("etcdv2_directory", "key "+plan.Key.ValueString(), req.Config, req.State))...)
+	}
+
+	// Nothing to refresh on create
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	if !plan.RefreshOnApply.ValueBool() || plan.TTL.IsNull() {
+		return
+	}
+
+	// Marking the expiration unknown forces an Update, which resets the TTL
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("expiration"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("modified_index"), types.Int64Unknown())...)
+}
+
+func (r *DirectoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data DirectoryResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(provider.checkChangeWindow(data.RespectChangeWindow)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	kApi, err := provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	directory, err := kApi.Set(context.Background(), data.Key.ValueString(), "", &clientv2.SetOptions{
+		Dir:       true,
+		PrevExist: clientv2.PrevNoExist,
+		TTL:       time.Duration(data.TTL.ValueInt64()) * time.Second,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcd directory",
+			errorDetail(err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(provider.recordChange(ctx, changeCreate, directory.Node.Key, prevIndex(directory.PrevNode), directory.Node.ModifiedIndex)...)
+
+	data.setNode(directory.Node)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DirectoryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data DirectoryResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new etcd client from config
+	kApi, err := provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	directory, err := kApi.Get(context.Background(), data.Key.ValueString(), nil)
+	if clientv2.IsKeyNotFound(err) {
+		// The directory has expired or was removed outside of Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd directory",
+			errorDetail(err),
+		)
+		return
+	}
+
+	if !directory.Node.Dir {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd directory",
+			"The key "+data.Key.ValueString()+" exists but is not a directory",
+		)
+		return
+	}
+
+	data.setNode(directory.Node)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DirectoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state DirectoryResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(provider.checkChangeWindow(data.RespectChangeWindow)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	if !data.RequireQuorumMembers.IsNull() {
+		if err := provider.requireHealthyMembers(ctx, data.RequireQuorumMembers.ValueInt64()); err != nil {
+			resp.Diagnostics.AddError(
+				"etcd cluster degraded",
+				"Refusing to update while the cluster is degraded: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// Create new etcd client from config
+	kApi, err := provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	if !data.MaxChildren.IsNull() {
+		err := checkMaxChildren(ctx, kApi, data.Key.ValueString(), data.MaxChildren.ValueInt64(), nil, nil)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_children"),
+				"Too many etcd directory entries",
+				errorDetail(err),
+			)
+			return
+		}
+	}
+
+	opts := &clientv2.SetOptions{
+		PrevExist: clientv2.PrevExist,
+		TTL:       time.Duration(data.TTL.ValueInt64()) * time.Second,
+	}
+
+	// An unchanged TTL is refreshed so watchers are not notified
+	if !data.TTL.IsNull() && data.TTL.Equal(state.TTL) {
+		opts.Refresh = true
+	} else {
+		opts.Dir = true
+	}
+
+	directory, err := kApi.Set(context.Background(), data.Key.ValueString(), "", opts)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update etcd directory",
+			errorDetail(err),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(provider.recordChange(ctx, changeUpdate, directory.Node.Key, prevIndex(directory.PrevNode), directory.Node.ModifiedIndex)...)
+
+	data.setNode(directory.Node)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DirectoryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data DirectoryResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(provider.checkChangeWindow(data.RespectChangeWindow)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	if !data.RequireQuorumMembers.IsNull() {
+		if err := provider.requireHealthyMembers(ctx, data.RequireQuorumMembers.ValueInt64()); err != nil {
+			resp.Diagnostics.AddError(
+				"etcd cluster degraded",
+				"Refusing to delete while the cluster is degraded: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// Create new etcd client from config
+	kApi, err := provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	deleted, err := kApi.Delete(context.Background(), data.Key.ValueString(), &clientv2.DeleteOptions{Dir: true})
+	if err != nil && !clientv2.IsKeyNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error when trying to Delete etcd directory",
+			errorDetail(err),
+		)
+		return
+	}
+
+	if err == nil {
+		resp.Diagnostics.Append(provider.recordChange(ctx, changeDelete, deleted.Node.Key, prevIndex(deleted.PrevNode), deleted.Index)...)
+	}
+}
+
+// setNode copies the server side view of the directory into the model.
+func (m *DirectoryResourceModel) setNode(node *clientv2.Node) {
+	m.ModifiedIndex = types.Int64Value(int64(node.ModifiedIndex))
+
+	if node.Expiration == nil {
+		m.Expiration = types.StringNull()
+		return
+	}
+
+	m.Expiration = types.StringValue(node.Expiration.Format(time.RFC3339))
+}
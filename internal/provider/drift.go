@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// driftSummary is shared by every drift warning, so Terraform groups them.
+const driftSummary = "etcd drift detected outside of Terraform"
+
+// Kinds of drift the report counts.
+const (
+	driftValues = "values"
+	driftRoles  = "roles"
+	driftUsers  = "users"
+)
+
+// driftReport collects drift detected while refreshing resources so that it
+// is surfaced as a warning rather than being silently absorbed into state.
+// Every warning carries the cumulative report so far, so the last one of a
+// refresh lists every drifted key, role and user with counts per kind.
+type driftReport struct {
+	mu sync.Mutex
+
+	// changes holds the changes recorded per kind and subject
+	changes map[string]map[string][]string
+}
+
+// record adds the changes of subject to the report and returns a warning with
+// the report so far. Each subject is only reported once.
+func (d *driftReport) record(kind, subject string, changes []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.changes == nil {
+		d.changes = make(map[string]map[string][]string)
+	}
+	if d.changes[kind] == nil {
+		d.changes[kind] = make(map[string][]string)
+	}
+	if _, ok := d.changes[kind][subject]; ok {
+		return diags
+	}
+	d.changes[kind][subject] = changes
+
+	diags.AddWarning(driftSummary, d.detail())
+	return diags
+}
+
+// detail renders the counts and sorted subjects of every kind of drift.
+func (d *driftReport) detail() string {
+	kinds := []struct {
+		kind, title string
+	}{
+		{driftValues, "Keyvalue values"},
+		{driftRoles, "Role permissions"},
+		{driftUsers, "User roles"},
+	}
+
+	var detail strings.Builder
+	fmt.Fprintf(&detail, "Changed since they were last applied: %d values, %d roles, %d users.\n",
+		len(d.changes[driftValues]), len(d.changes[driftRoles]), len(d.changes[driftUsers]))
+
+	for _, k := range kinds {
+		subjects := make([]string, 0, len(d.changes[k.kind]))
+		for subject := range d.changes[k.kind] {
+			subjects = append(subjects, subject)
+		}
+		if len(subjects) == 0 {
+			continue
+		}
+		sort.Strings(subjects)
+
+		fmt.Fprintf(&detail, "\n%s (%d):\n", k.title, len(subjects))
+		for _, subject := range subjects {
+			fmt.Fprintf(&detail, "  - %s\n", subject)
+			for _, change := range d.changes[k.kind][subject] {
+				fmt.Fprintf(&detail, "      %s\n", change)
+			}
+		}
+	}
+
+	return detail.String()
+}
+
+// recordValue records that the value stored at key no longer matches state.
+func (d *driftReport) recordValue(key string) diag.Diagnostics {
+	return d.record(driftValues, key, nil)
+}
+
+// recordPermissions records that the permissions of a role no longer match
+// state, listing the keys granted and revoked outside of Terraform.
+func (d *driftReport) recordPermissions(role string, granted, revoked clientv2.Permissions) diag.Diagnostics {
+	var changes []string
+	for _, change := range []struct {
+		what string
+		keys []string
+	}{
+		{"read granted", granted.KV.Read},
+		{"write granted", granted.KV.Write},
+		{"read revoked", revoked.KV.Read},
+		{"write revoked", revoked.KV.Write},
+	} {
+		keys := append([]string{}, change.keys...)
+		sort.Strings(keys)
+		for _, k := range keys {
+			changes = append(changes, change.what+": "+k)
+		}
+	}
+
+	return d.record(driftRoles, role, changes)
+}
+
+// recordRoles records that the roles of a user no longer match state.
+func (d *driftReport) recordRoles(user string, granted, revoked []string) diag.Diagnostics {
+	var changes []string
+	for _, role := range granted {
+		changes = append(changes, "granted: "+role)
+	}
+	for _, role := range revoked {
+		changes = append(changes, "revoked: "+role)
+	}
+
+	return d.record(driftUsers, user, changes)
+}
+
+// logDrift emits a structured event for a single attribute that changed in
+// etcd since it was last stored in state.
+func logDrift(ctx context.Context, key, attribute string, prior, current attr.Value) {
+	tflog.Info(ctx, "etcd drift detected", map[string]interface{}{
+		"key":       key,
+		"attribute": attribute,
+		"prior":     prior.String(),
+		"current":   current.String(),
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestDriftReport(t *testing.T) {
+	var d driftReport
+
+	var perms clientv2.Permissions
+	perms.KV.Read = []string{"/app/*"}
+
+	for _, diags := range []diag.Diagnostics{
+		d.recordValue("/app/b"),
+		d.recordValue("/app/a"),
+		d.recordPermissions("app", perms, clientv2.Permissions{}),
+		d.recordRoles("deployer", []string{"app"}, nil),
+	} {
+		if n := diags.WarningsCount(); n != 1 {
+			t.Fatalf("got %d warnings, want 1", n)
+		}
+	}
+
+	// A key drifting again is not reported twice
+	if diags := d.recordValue("/app/a"); diags.WarningsCount() != 0 {
+		t.Errorf("drift of /app/a was reported twice")
+	}
+
+	diags := d.recordValue("/app/c")
+	if diags[0].Summary() != driftSummary {
+		t.Errorf("got summary %q, want %q", diags[0].Summary(), driftSummary)
+	}
+
+	detail := diags[0].Detail()
+	for _, want := range []string{
+		"3 values, 1 roles, 1 users",
+		"Keyvalue values (3):\n  - /app/a\n  - /app/b\n  - /app/c\n",
+		"Role permissions (1):\n  - app\n      read granted: /app/*\n",
+		"User roles (1):\n  - deployer\n      granted: app\n",
+	} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("detail %q does not contain %q", detail, want)
+		}
+	}
+}
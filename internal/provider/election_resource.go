@@ -0,0 +1,321 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                   = &ElectionResource{}
+	_ resource.ResourceWithConfigure      = &ElectionResource{}
+	_ resource.ResourceWithValidateConfig = &ElectionResource{}
+	_ resource.ResourceWithModifyPlan     = &ElectionResource{}
+)
+
+func NewElectionResource() resource.Resource {
+	return &ElectionResource{}
+}
+
+// ElectionResource defines the resource implementation
+type ElectionResource struct {
+	provider *etcdv2ProviderData
+}
+
+// ElectionResourceModel describes the resource data model.
+type ElectionResourceModel struct {
+	Key       types.String `tfsdk:"key"`
+	Candidate types.String `tfsdk:"candidate"`
+	TTL       types.Int64  `tfsdk:"ttl"`
+	IsLeader  types.Bool   `tfsdk:"is_leader"`
+	Leader    types.String `tfsdk:"leader"`
+}
+
+func (r *ElectionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_election"
+}
+
+func (r *ElectionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Campaigns for leadership by creating a TTL key holding the candidate only if the key does not exist yet, " +
+			"so deployment pipelines can elect a primary. The candidate holding the key leads until it expires or the resource is destroyed. " +
+			"A refresh that finds leadership has changed removes the resource from state, so the next apply campaigns again.",
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The location of the election key (e.g. '/app/primary')",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"candidate": schema.StringAttribute{
+				MarkdownDescription: "The value identifying this candidate, e.g. `blue` or `green`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ttl": schema.Int64Attribute{
+				MarkdownDescription: "The time to live of the election key in seconds. A leader refreshes it on every apply",
+				Required:            true,
+			},
+			"is_leader": schema.BoolAttribute{
+				MarkdownDescription: "Whether the candidate holds the election key",
+				Computed:            true,
+			},
+			"leader": schema.StringAttribute{
+				MarkdownDescription: "The candidate holding the election key at apply time",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *ElectionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data ElectionResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.TTL.IsUnknown() && !data.TTL.IsNull() && data.TTL.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ttl"),
+			"Invalid election TTL",
+			"The ttl must be at least 1 second, an election key that never expires would never be given up by a lost leader.",
+		)
+	}
+}
+
+func (r *ElectionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(r.provider.checkPlannedKeys(ctx, req.Plan, "key")...)
+}
+
+func (r *ElectionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	r.provider = provider
+}
+
+func (r *ElectionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ElectionResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.campaign(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ElectionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ElectionResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new etcd client from config
+	kApi, err := r.provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	election, err := kApi.Get(ctx, data.Key.ValueString(), nil)
+	if clientv2.IsKeyNotFound(err) {
+		// The leader expired or resigned, so campaign again on the next apply
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd election",
+			errorDetail(err),
+		)
+		return
+	}
+
+	if election.Node.Value != data.Leader.ValueString() {
+		// Leadership changed hands since the last apply
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ElectionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ElectionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.campaign(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ElectionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ElectionResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.IsLeader.ValueBool() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	kApi, err := r.provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Resign, unless another candidate has taken over in the meantime
+	_, err = kApi.Delete(ctx, data.Key.ValueString(), &clientv2.DeleteOptions{PrevValue: data.Candidate.ValueString()})
+	if err != nil && !clientv2.IsKeyNotFound(err) && !isCompareFailed(err) {
+		resp.Diagnostics.AddError(
+			"Error when trying to Delete etcd election",
+			errorDetail(err),
+		)
+		return
+	}
+}
+
+// campaign creates the election key for the candidate if it does not exist,
+// refreshing its TTL if the candidate already leads, and records the leader.
+func (r *ElectionResource) campaign(ctx context.Context, data *ElectionResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		diags.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return diags
+	}
+
+	// Create new etcd client from config
+	kApi, err := r.provider.keysAPI()
+	if err != nil {
+		diags.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return diags
+	}
+
+	key, candidate := data.Key.ValueString(), data.Candidate.ValueString()
+	ttl := time.Duration(data.TTL.ValueInt64()) * time.Second
+
+	_, err = kApi.Set(ctx, key, candidate, &clientv2.SetOptions{PrevExist: clientv2.PrevNoExist, TTL: ttl})
+	if err == nil {
+		data.IsLeader = types.BoolValue(true)
+		data.Leader = types.StringValue(candidate)
+		return diags
+	}
+
+	if clientErr, ok := err.(clientv2.Error); !ok || clientErr.Code != clientv2.ErrorCodeNodeExist {
+		diags.AddError(
+			"Unable to Write etcd election",
+			errorDetail(err),
+		)
+		return diags
+	}
+
+	// Someone holds the key already, which may be this candidate
+	_, err = kApi.Set(ctx, key, candidate, &clientv2.SetOptions{PrevValue: candidate, TTL: ttl})
+	if err == nil {
+		data.IsLeader = types.BoolValue(true)
+		data.Leader = types.StringValue(candidate)
+		return diags
+	}
+	if !isCompareFailed(err) && !clientv2.IsKeyNotFound(err) {
+		diags.AddError(
+			"Unable to Write etcd election",
+			errorDetail(err),
+		)
+		return diags
+	}
+
+	election, err := kApi.Get(ctx, key, nil)
+	if clientv2.IsKeyNotFound(err) {
+		// The leader expired just now, leave it to the next apply to campaign
+		data.IsLeader = types.BoolValue(false)
+		data.Leader = types.StringNull()
+		return diags
+	}
+	if err != nil {
+		diags.AddError(
+			"Unable to Read etcd election",
+			errorDetail(err),
+		)
+		return diags
+	}
+
+	data.IsLeader = types.BoolValue(election.Node.Value == candidate)
+	data.Leader = types.StringValue(election.Node.Value)
+
+	return diags
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+const (
+	// endpointFailureThreshold is how many consecutive failed requests demote
+	// an endpoint.
+	endpointFailureThreshold = 2
+
+	// endpointCooldown is how long a demoted endpoint is skipped for before
+	// requests are sent to it again.
+	endpointCooldown = 30 * time.Second
+)
+
+// endpointHealth tracks failed requests per endpoint across the clients the
+// provider creates. Every client starts from the full endpoint list, so
+// without it a dead endpoint costs a request timeout again and again.
+type endpointHealth struct {
+	mu       sync.Mutex
+	failures map[string]int
+	demoted  map[string]time.Time
+}
+
+func newEndpointHealth() *endpointHealth {
+	return &endpointHealth{
+		failures: make(map[string]int),
+		demoted:  make(map[string]time.Time),
+	}
+}
+
+// record counts a failed request to endpoint, demoting it once it failed
+// endpointFailureThreshold times in a row, and resets the count on success.
+func (h *endpointHealth) record(endpoint string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err == nil {
+		delete(h.failures, endpoint)
+		delete(h.demoted, endpoint)
+		return
+	}
+
+	h.failures[endpoint]++
+	if h.failures[endpoint] >= endpointFailureThreshold {
+		h.demoted[endpoint] = time.Now().Add(endpointCooldown)
+	}
+}
+
+// filter returns the endpoints that are not demoted. All endpoints are
+// returned if every one of them is demoted, so requests are still attempted.
+func (h *endpointHealth) filter(endpoints []string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	healthy := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if until, ok := h.demoted[endpointOf(endpoint)]; ok && now.Before(until) {
+			continue
+		}
+		healthy = append(healthy, endpoint)
+	}
+
+	if len(healthy) == 0 {
+		return endpoints
+	}
+	return healthy
+}
+
+// endpointOf returns the scheme and host of rawURL, which requests to the
+// endpoint are recorded under.
+func endpointOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	return u.Scheme + "://" + u.Host
+}
+
+// healthTransport records the outcome of every request in health. Only
+// requests that got no response count as failures, since any response means
+// the endpoint is reachable.
+type healthTransport struct {
+	clientv2.CancelableTransport
+
+	health *endpointHealth
+}
+
+func (t *healthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.CancelableTransport.RoundTrip(req)
+
+	// Requests canceled by the caller say nothing about the endpoint
+	if err != nil && req.Context().Err() != nil {
+		return resp, err
+	}
+	t.health.record(req.URL.Scheme+"://"+req.URL.Host, err)
+
+	return resp, err
+}
+
+// clientConfig returns the client configuration with demoted endpoints left
+// out.
+func (p *etcdv2ProviderData) clientConfig() clientv2.Config {
+	cfg := *p.cfg
+	cfg.Endpoints = p.endpoints.filter(cfg.Endpoints)
+
+	return cfg
+}
@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// errorDetail renders err for a diagnostic detail. etcd API errors are
+// expanded into one "field: value" line per attribute after the message, so
+// automation reading Terraform's JSON output can tell auth failures, missing
+// keys and failed compare-and-swaps apart.
+func errorDetail(err error) string {
+	var detail strings.Builder
+	detail.WriteString(err.Error())
+
+	var apiErr clientv2.Error
+	var clusterErr *clientv2.ClusterError
+
+	switch {
+	case errors.As(err, &apiErr):
+		fmt.Fprintf(&detail, "\n\netcd_error_code: %d", apiErr.Code)
+		fmt.Fprintf(&detail, "\netcd_error_message: %s", apiErr.Message)
+		fmt.Fprintf(&detail, "\netcd_error_cause: %s", apiErr.Cause)
+		fmt.Fprintf(&detail, "\netcd_error_index: %d", apiErr.Index)
+	case errors.As(err, &clusterErr):
+		detail.WriteString("\n\netcd_error_code: cluster_unavailable")
+	}
+
+	return detail.String()
+}
+
+// isCompareFailed reports whether err is a failed compare-and-swap.
+func isCompareFailed(err error) bool {
+	clientErr, ok := err.(clientv2.Error)
+	return ok && clientErr.Code == clientv2.ErrorCodeTestFailed
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// ttlUntil converts an RFC3339 deadline into the TTL to write now, rounded up
+// to whole seconds as etcd does not accept fractions.
+func ttlUntil(expiresAt string) (time.Duration, error) {
+	deadline, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return 0, fmt.Errorf("expires_at is not an RFC3339 timestamp: %w", err)
+	}
+
+	ttl := time.Until(deadline)
+	if ttl <= 0 {
+		return 0, fmt.Errorf("expires_at %s has already passed", expiresAt)
+	}
+
+	return ttl.Truncate(time.Second) + time.Second, nil
+}
+
+// applyExpiry sets the TTL of the node just written so that it expires at
+// expiresAt. The TTL is refreshed in place, conditional on the write not
+// having been superseded, so watchers see a single change.
+func applyExpiry(ctx context.Context, kApi clientv2.KeysAPI, written *clientv2.Node, expiresAt string) (*clientv2.Node, error) {
+	ttl, err := ttlUntil(expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := kApi.Set(ctx, written.Key, "", &clientv2.SetOptions{
+		PrevExist: clientv2.PrevExist,
+		PrevIndex: written.ModifiedIndex,
+		TTL:       ttl,
+		Refresh:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Node, nil
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+const (
+	// fencingKeyPrefix is the directory fencing keys are written under.
+	fencingKeyPrefix = "/terraform/locks/"
+
+	// defaultFencingTTL is used when fencing_ttl is not configured.
+	defaultFencingTTL = 300 * time.Second
+)
+
+// applyFence guards a workspace against concurrent applies by holding a TTL
+// key for the lifetime of this provider process. It is acquired lazily on the
+// first mutating operation so that plans never take the fence, refreshed for
+// as long as the apply runs and deleted when the provider shuts down. A
+// crashed apply leaves the key to expire after its TTL.
+type applyFence struct {
+	key   string
+	ttl   time.Duration
+	owner string
+
+	renewal *heartbeats
+
+	once sync.Once
+	err  error
+}
+
+func newApplyFence(workspace string, ttl time.Duration) *applyFence {
+	hostname, _ := os.Hostname()
+
+	return &applyFence{
+		key:     fencingKeyPrefix + workspace,
+		ttl:     ttl,
+		owner:   fmt.Sprintf("%s/%d/%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		renewal: newHeartbeats(),
+	}
+}
+
+// acquire writes the fencing key if it is absent and keeps refreshing it. Only
+// the first call talks to etcd; later calls return the original outcome.
+func (f *applyFence) acquire(ctx context.Context, kApi clientv2.KeysAPI) error {
+	f.once.Do(func() {
+		_, err := kApi.Set(ctx, f.key, f.owner, &clientv2.SetOptions{
+			PrevExist: clientv2.PrevNoExist,
+			TTL:       f.ttl,
+		})
+		if err == nil {
+			f.renewal.start(ctx, kApi, f.key, f.owner, f.ttl)
+			return
+		}
+
+		if clientErr, ok := err.(clientv2.Error); ok && clientErr.Code == clientv2.ErrorCodeNodeExist {
+			holder, getErr := kApi.Get(ctx, f.key, nil)
+			if getErr != nil {
+				f.err = fmt.Errorf("fencing key %s is held by another apply", f.key)
+				return
+			}
+			f.err = fmt.Errorf("fencing key %s is held by %q, expiring in %ds", f.key, holder.Node.Value, holder.Node.TTL)
+			return
+		}
+
+		f.err = err
+	})
+
+	return f.err
+}
+
+// acquireFence takes the apply fence when fencing is enabled.
+func (p *etcdv2ProviderData) acquireFence(ctx context.Context) error {
+	if p.fence == nil {
+		return nil
+	}
+
+	kApi, err := p.keysAPI()
+	if err != nil {
+		return err
+	}
+
+	return p.fence.acquire(ctx, kApi)
+}
+
+// release deletes the fencing key if this process still holds it.
+func (f *applyFence) release(ctx context.Context) {
+	f.renewal.release(ctx)
+}
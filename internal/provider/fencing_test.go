@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"terraform-provider-etcdv2/internal/testetcd"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccApplyFence(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	config := etcd.ProviderConfig(`fencing_workspace = "test"`, `fencing_ttl = 3`) + testAccKeyvalueResourceConfig("one")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Another apply holds the fence
+			{
+				PreConfig:   func() { etcd.Set("/terraform/locks/test", "someone else") },
+				Config:      config,
+				ExpectError: regexp.MustCompile(`held by "someone else"`),
+			},
+			{
+				PreConfig: func() { etcd.Delete("/terraform/locks/test") },
+				Config:    config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckEtcdValue(etcd, "/app/test", "one"),
+					testAccCheckFenceHolder(etcd, "/terraform/locks/test"),
+					// The fence outlives its TTL while the apply runs
+					func(*terraform.State) error {
+						time.Sleep(4 * time.Second)
+						return nil
+					},
+					testAccCheckFenceHolder(etcd, "/terraform/locks/test"),
+					// and is released once the provider stops
+					func(*terraform.State) error {
+						testAccShutdown()
+						return nil
+					},
+					testAccCheckEtcdMissing(etcd, "/terraform/locks/test"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckFenceHolder checks that key is held by an apply of this test.
+func testAccCheckFenceHolder(etcd *testetcd.Server, key string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		holder, ok := etcd.Value(key)
+		if !ok {
+			return fmt.Errorf("fencing key %s is not held", key)
+		}
+		if holder == "someone else" {
+			return fmt.Errorf("fencing key %s is still held by %q", key, holder)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// heartbeats keeps TTL keys alive for as long as the provider process runs,
+// which is the duration of the apply. When the provider shuts down the keys
+// are released, and should it crash they expire after their TTL.
+type heartbeats struct {
+	mu      sync.Mutex
+	running map[string]*heartbeat
+}
+
+// heartbeat is a single running refresh loop.
+type heartbeat struct {
+	cancel context.CancelFunc
+	kApi   clientv2.KeysAPI
+	value  string
+}
+
+func newHeartbeats() *heartbeats {
+	return &heartbeats{
+		running: make(map[string]*heartbeat),
+	}
+}
+
+// start refreshes key every third of ttl until stop is called or a refresh
+// fails. Refreshes only succeed while key still holds value, so a key taken
+// over by someone else is left alone. Starting an already running key
+// restarts it with the new value and ttl.
+func (h *heartbeats) start(ctx context.Context, kApi clientv2.KeysAPI, key, value string, ttl time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if hb, ok := h.running[key]; ok {
+		hb.cancel()
+	}
+
+	// The request context ends with the RPC, so refreshes run detached from it
+	refreshCtx, cancel := context.WithCancel(context.Background())
+	hb := &heartbeat{cancel: cancel, kApi: kApi, value: value}
+	h.running[key] = hb
+
+	go func() {
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-refreshCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			_, err := kApi.Set(refreshCtx, key, "", &clientv2.SetOptions{
+				PrevExist: clientv2.PrevExist,
+				PrevValue: value,
+				TTL:       ttl,
+				Refresh:   true,
+			})
+			if err != nil {
+				tflog.Warn(ctx, "etcd heartbeat refresh failed, no longer refreshing", map[string]interface{}{
+					"key":   key,
+					"error": err.Error(),
+				})
+				h.remove(key, hb)
+				return
+			}
+		}
+	}()
+}
+
+// stop ends the refreshes of key, if any.
+func (h *heartbeats) stop(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if hb, ok := h.running[key]; ok {
+		hb.cancel()
+		delete(h.running, key)
+	}
+}
+
+// remove forgets hb if it is still the running loop for key.
+func (h *heartbeats) remove(key string, hb *heartbeat) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.running[key] == hb {
+		hb.cancel()
+		delete(h.running, key)
+	}
+}
+
+// release stops every refresh loop and deletes the keys that still hold the
+// value they were written with. Failing deletes are logged, the keys then
+// expire after their TTL.
+func (h *heartbeats) release(ctx context.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for key, hb := range h.running {
+		hb.cancel()
+		delete(h.running, key)
+
+		_, err := hb.kApi.Delete(ctx, key, &clientv2.DeleteOptions{PrevValue: hb.value})
+		if err != nil && !clientv2.IsKeyNotFound(err) && !isCompareFailed(err) {
+			tflog.Warn(ctx, "Unable to release etcd heartbeat, leaving it to expire", map[string]interface{}{
+				"key":   key,
+				"error": err.Error(),
+			})
+		}
+	}
+}
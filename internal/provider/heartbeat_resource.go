@@ -0,0 +1,273 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                   = &HeartbeatResource{}
+	_ resource.ResourceWithConfigure      = &HeartbeatResource{}
+	_ resource.ResourceWithValidateConfig = &HeartbeatResource{}
+	_ resource.ResourceWithModifyPlan     = &HeartbeatResource{}
+)
+
+func NewHeartbeatResource() resource.Resource {
+	return &HeartbeatResource{}
+}
+
+// HeartbeatResource defines the resource implementation
+type HeartbeatResource struct {
+	provider *etcdv2ProviderData
+}
+
+// HeartbeatResourceModel describes the resource data model.
+type HeartbeatResourceModel struct {
+	Key   types.String `tfsdk:"key"`
+	Value types.String `tfsdk:"value"`
+	TTL   types.Int64  `tfsdk:"ttl"`
+
+	ModifiedIndex types.Int64 `tfsdk:"modified_index"`
+}
+
+func (r *HeartbeatResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_heartbeat"
+}
+
+func (r *HeartbeatResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Writes a TTL key on every apply and keeps refreshing it for the rest of the apply, so applications can tell a deployment is in progress. " +
+			"The key is deleted when the apply finishes, or expires after `ttl` should the provider crash.",
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The location of the heartbeat key (e.g. '/app/deployer')",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "The value of the heartbeat key",
+				Optional:            true,
+			},
+			"ttl": schema.Int64Attribute{
+				MarkdownDescription: "The time to live of the key in seconds. The key is refreshed every third of it",
+				Required:            true,
+			},
+			"modified_index": schema.Int64Attribute{
+				MarkdownDescription: "The index at which the key was last written. Unknown in every plan, so each apply writes the key again",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *HeartbeatResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data HeartbeatResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.TTL.IsUnknown() && !data.TTL.IsNull() && data.TTL.ValueInt64() < 3 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ttl"),
+			"Invalid heartbeat TTL",
+			"The ttl must be at least 3 seconds so the key can be refreshed before it expires.",
+		)
+	}
+}
+
+func (r *HeartbeatResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(r.provider.checkPlannedKeys(ctx, req.Plan, "key")...)
+
+	// Nothing to force on create or destroy
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	// The key may still exist from an earlier apply without being refreshed
+	// by this one, so marking the index unknown forces an Update
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("modified_index"), types.Int64Unknown())...)
+}
+
+func (r *HeartbeatResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	r.provider = provider
+}
+
+func (r *HeartbeatResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data HeartbeatResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.beat(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HeartbeatResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data HeartbeatResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new etcd client from config
+	kApi, err := r.provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	heartbeat, err := kApi.Get(ctx, data.Key.ValueString(), nil)
+	if clientv2.IsKeyNotFound(err) {
+		// The previous apply has finished and the key expired
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd heartbeat",
+			errorDetail(err),
+		)
+		return
+	}
+
+	if !data.Value.IsNull() {
+		data.Value = types.StringValue(heartbeat.Node.Value)
+	}
+	data.ModifiedIndex = types.Int64Value(int64(heartbeat.Node.ModifiedIndex))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HeartbeatResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data HeartbeatResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.beat(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HeartbeatResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data HeartbeatResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.provider.heartbeats.stop(data.Key.ValueString())
+
+	// Create new etcd client from config
+	kApi, err := r.provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	_, err = kApi.Delete(ctx, data.Key.ValueString(), nil)
+	if err != nil && !clientv2.IsKeyNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error when trying to Delete etcd heartbeat",
+			errorDetail(err),
+		)
+		return
+	}
+}
+
+// beat writes the heartbeat key and keeps it refreshed for the rest of the
+// apply. The key is released on shutdown.
+func (r *HeartbeatResource) beat(ctx context.Context, data *HeartbeatResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		diags.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return diags
+	}
+
+	// Create new etcd client from config
+	kApi, err := r.provider.keysAPI()
+	if err != nil {
+		diags.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return diags
+	}
+
+	ttl := time.Duration(data.TTL.ValueInt64()) * time.Second
+
+	heartbeat, err := kApi.Set(ctx, data.Key.ValueString(), data.Value.ValueString(), &clientv2.SetOptions{TTL: ttl})
+	if err != nil {
+		diags.AddError(
+			"Unable to Write etcd heartbeat",
+			errorDetail(err),
+		)
+		return diags
+	}
+
+	data.ModifiedIndex = types.Int64Value(int64(heartbeat.Node.ModifiedIndex))
+
+	r.provider.heartbeats.start(ctx, kApi, data.Key.ValueString(), data.Value.ValueString(), ttl)
+
+	return diags
+}
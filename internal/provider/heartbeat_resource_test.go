@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"terraform-provider-etcdv2/internal/testetcd"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccHeartbeatResource(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	var written int64
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: etcd.ProviderConfig() + testAccHeartbeatResourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckEtcdValue(etcd, "/app/deployer", "terraform"),
+					resource.TestCheckResourceAttrWith("etcdv2_heartbeat.test", "modified_index", func(value string) error {
+						var err error
+						written, err = strconv.ParseInt(value, 10, 64)
+						return err
+					}),
+					// The key outlives its TTL while it is refreshed
+					func(*terraform.State) error {
+						time.Sleep(4 * time.Second)
+						return nil
+					},
+					testAccCheckEtcdValue(etcd, "/app/deployer", "terraform"),
+				),
+				// Every apply writes the key again
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: etcd.ProviderConfig() + testAccHeartbeatResourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("etcdv2_heartbeat.test", "modified_index", func(value string) error {
+						if index, _ := strconv.ParseInt(value, 10, 64); index <= written {
+							return fmt.Errorf("modified_index %s was not advanced by the apply from %d", value, written)
+						}
+
+						return nil
+					}),
+					// The key is released once the provider stops
+					func(*terraform.State) error {
+						testAccShutdown()
+						return nil
+					},
+					testAccCheckEtcdMissing(etcd, "/app/deployer"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+		CheckDestroy: testAccCheckEtcdMissing(etcd, "/app/deployer"),
+	})
+}
+
+const testAccHeartbeatResourceConfig = `
+resource "etcdv2_heartbeat" "test" {
+  key   = "/app/deployer"
+  value = "terraform"
+  ttl   = 3
+}
+`
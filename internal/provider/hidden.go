@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// revealHidden adds the hidden children of dir to its nodes. etcd v2 leaves
+// nodes whose names start with an underscore out of listings, so they can only
+// be found with a Get by name. The names tried are the metadata and history
+// directories this provider creates, plus extra. Subdirectories are searched
+// too when recursive is set.
+func revealHidden(ctx context.Context, kApi clientv2.KeysAPI, dir *clientv2.Node, extra []string, recursive bool) error {
+	candidates := map[string]bool{metadataDir: true}
+	for _, name := range extra {
+		candidates["_"+strings.TrimPrefix(name, "_")] = true
+	}
+	for _, n := range dir.Nodes {
+		if !n.Dir {
+			candidates[path.Base(historyDir(n.Key))] = true
+		}
+	}
+
+	for _, n := range dir.Nodes {
+		// Some proxies list hidden nodes anyway
+		delete(candidates, path.Base(n.Key))
+	}
+
+	for name := range candidates {
+		hidden, err := kApi.Get(ctx, path.Join(dir.Key, name), &clientv2.GetOptions{Recursive: recursive, Sort: true})
+		if clientv2.IsKeyNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if !recursive {
+			// Match the listing, which leaves out the children of subdirectories
+			hidden.Node.Nodes = nil
+		}
+		dir.Nodes = append(dir.Nodes, hidden.Node)
+	}
+
+	sort.Slice(dir.Nodes, func(i, j int) bool { return dir.Nodes[i].Key < dir.Nodes[j].Key })
+
+	if !recursive {
+		return nil
+	}
+
+	for _, n := range dir.Nodes {
+		if !n.Dir || strings.HasPrefix(path.Base(n.Key), "_") {
+			continue
+		}
+		if err := revealHidden(ctx, kApi, n, extra, recursive); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
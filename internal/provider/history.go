@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// defaultHistoryRetention is used when history_retention is not configured.
+const defaultHistoryRetention = 10
+
+// historyDir returns the directory previous values of key are copied to. The
+// leading underscore hides it from listings of the parent directory.
+func historyDir(key string) string {
+	dir, name := path.Split(key)
+	return dir + "_" + name + "_history"
+}
+
+// shadowHistory copies the current value of key into its history directory
+// before it is overwritten, then prunes all but the newest retention copies.
+// Entries are named by the zero padded modified index of the copied value so
+// that they sort chronologically.
+func shadowHistory(ctx context.Context, kApi clientv2.KeysAPI, key string, retention int64) error {
+	current, err := kApi.Get(ctx, key, nil)
+	if clientv2.IsKeyNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	dir := historyDir(key)
+
+	entry := fmt.Sprintf("%s/%020d", dir, current.Node.ModifiedIndex)
+	if _, err := kApi.Set(ctx, entry, current.Node.Value, nil); err != nil {
+		return err
+	}
+
+	history, err := kApi.Get(ctx, dir, &clientv2.GetOptions{Sort: true})
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(history.Node.Nodes)-int(retention); i++ {
+		_, err := kApi.Delete(ctx, history.Node.Nodes[i].Key, nil)
+		if err != nil && !clientv2.IsKeyNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// hostLookupTimeout bounds the DNS check made while validating host.
+const hostLookupTimeout = 5 * time.Second
+
+// hostError describes why a configured host cannot be used, along with a hint
+// on how to fix it.
+type hostError struct {
+	Summary     string
+	Remediation string
+}
+
+// validateHost checks that host is an http(s) URL with an explicit port whose
+// hostname resolves. Resolution is skipped when resolve is false, such as when
+// a proxy resolves names on the far side.
+func validateHost(ctx context.Context, host string, resolve bool) *hostError {
+	u, err := url.Parse(host)
+	if err != nil {
+		return &hostError{
+			Summary:     fmt.Sprintf("The host %q is not a valid URL: %s", host, err),
+			Remediation: "Set host to a URL such as http://localhost:2379",
+		}
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return &hostError{
+			Summary:     fmt.Sprintf("The host %q is missing an http:// or https:// scheme", host),
+			Remediation: "Prefix the host with http:// or https://, e.g. http://" + host,
+		}
+	}
+
+	if u.Port() == "" {
+		return &hostError{
+			Summary:     fmt.Sprintf("The host %q does not include a port", host),
+			Remediation: "Add the etcd client port to the host, e.g. " + u.Scheme + "://" + u.Hostname() + ":2379",
+		}
+	}
+
+	if !resolve || net.ParseIP(u.Hostname()) != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hostLookupTimeout)
+	defer cancel()
+
+	if _, err := net.DefaultResolver.LookupHost(ctx, u.Hostname()); err != nil {
+		return &hostError{
+			Summary:     fmt.Sprintf("The hostname %q could not be resolved: %s", u.Hostname(), err),
+			Remediation: "Check the hostname is spelled correctly and resolvable from where Terraform runs",
+		}
+	}
+
+	return nil
+}
+
+// withPathPrefix returns host with prefix appended to its path, so that the
+// client addresses the v2 API at <host><prefix>/v2. A trailing "/v2" on prefix
+// is tolerated since gateways are usually documented by their full API path.
+func withPathPrefix(host, prefix string) (string, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return "", err
+	}
+
+	prefix = strings.TrimSuffix(strings.TrimSuffix(prefix, "/"), "/v2")
+	u.Path = strings.TrimSuffix(path.Join("/", u.Path, prefix), "/")
+
+	return u.String(), nil
+}
+
+// keyURL returns the v2 keys API URL of key on endpoint, keeping any path
+// the endpoint has, such as a gateway prefix.
+func keyURL(endpoint, key string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("the endpoint %q must be an absolute URL such as http://localhost:2379", endpoint)
+	}
+
+	u.Path = path.Join("/", u.Path, "v2/keys", key)
+	u.RawQuery, u.Fragment = "", ""
+
+	return u.String(), nil
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// importID returns the value of field from an import ID. The ID is either the
+// plain value, or a composite such as "cluster=prod,key=/foo/bar" whose
+// cluster must name the configured cluster, so an identically-named object is
+// not imported from the wrong cluster.
+func (p *etcdv2ProviderData) importID(ctx context.Context, id, field string) (string, diag.Diagnostics) {
+	fields, diags := p.importFields(ctx, id, field)
+
+	return fields[field], diags
+}
+
+// importFields returns the fields of an import ID, which is either the plain
+// value of field or a composite of field, cluster and the optional fields.
+func (p *etcdv2ProviderData) importFields(ctx context.Context, id, field string, optional ...string) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	allowed := append([]string{field, "cluster"}, optional...)
+
+	composite := false
+	for _, name := range allowed {
+		composite = composite || strings.HasPrefix(id, name+"=")
+	}
+	if !composite {
+		return map[string]string{field: id}, diags
+	}
+
+	fields := make(map[string]string)
+	for _, part := range strings.Split(id, ",") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || !containsString(allowed, name) {
+			diags.AddError(
+				"Invalid import ID",
+				fmt.Sprintf("The import ID %q must be the %s itself or of the form cluster=<alias>,%s=<%s>.", id, field, field, field),
+			)
+			return nil, diags
+		}
+		fields[name] = value
+	}
+
+	if fields[field] == "" {
+		diags.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("The import ID %q does not set %s.", id, field),
+		)
+		return nil, diags
+	}
+
+	if cluster, ok := fields["cluster"]; ok && p.clusters[cluster] == nil {
+		if err := p.checkCluster(ctx, cluster); err != nil {
+			diags.AddError(
+				"Import ID targets another etcd cluster",
+				err.Error(),
+			)
+			return nil, diags
+		}
+	}
+
+	return fields, diags
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// importCluster returns the cluster of endpoints_by_name a composite import
+// ID names, or "" if it names none.
+func (p *etcdv2ProviderData) importCluster(id string) string {
+	for _, part := range strings.Split(id, ",") {
+		if name, value, ok := strings.Cut(part, "="); ok && name == "cluster" && p.clusters[value] != nil {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// checkCluster fails unless cluster is the configured cluster_alias or the ID
+// of the cluster the provider is connected to.
+func (p *etcdv2ProviderData) checkCluster(ctx context.Context, cluster string) error {
+	if p.clusterAlias != "" && cluster == p.clusterAlias {
+		return nil
+	}
+
+	client, err := clientv2.New(p.clientConfig())
+	if err != nil {
+		return err
+	}
+
+	headers, err := readClusterHeaders(ctx, client)
+	if err != nil {
+		return fmt.Errorf("unable to read the cluster ID: %w", err)
+	}
+
+	if headers.ClusterID != "" && cluster == headers.ClusterID {
+		return nil
+	}
+
+	if p.clusterAlias != "" {
+		return fmt.Errorf("the import ID names cluster %q, but the provider is configured for cluster %q (ID %s)", cluster, p.clusterAlias, headers.ClusterID)
+	}
+
+	return fmt.Errorf("the import ID names cluster %q, but the provider is connected to cluster ID %s and sets no cluster_alias", cluster, headers.ClusterID)
+}
+
+// privateKeyImportIndex holds the modified index an import was pinned to,
+// until the first refresh after the import has checked it.
+const privateKeyImportIndex = "import_index"
+
+// parseImportIndex parses the index field of an import ID, the modified
+// index the imported key is expected to be at.
+func parseImportIndex(fields map[string]string) (uint64, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	value, ok := fields["index"]
+	if !ok {
+		return 0, false, diags
+	}
+
+	index, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		diags.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("The index %q of the import ID is not a modified index.", value),
+		)
+		return 0, false, diags
+	}
+
+	return index, true, diags
+}
+
+// pinImportIndex records index as the modified index the first refresh must
+// find.
+func pinImportIndex(ctx context.Context, private privateSetter, index uint64) diag.Diagnostics {
+	raw, _ := json.Marshal(index)
+
+	return private.SetKey(ctx, privateKeyImportIndex, raw)
+}
+
+// clearImportIndex removes the pin once it has been checked. Private state
+// values must be JSON, so the pin is replaced with null.
+func clearImportIndex(ctx context.Context, private privateSetter) diag.Diagnostics {
+	return private.SetKey(ctx, privateKeyImportIndex, []byte("null"))
+}
+
+// importedIndex returns the modified index an import was pinned to, if any.
+func importedIndex(ctx context.Context, private privateGetter) (uint64, bool) {
+	raw, diags := private.GetKey(ctx, privateKeyImportIndex)
+	if diags.HasError() || len(raw) == 0 {
+		return 0, false
+	}
+
+	var index *uint64
+	if err := json.Unmarshal(raw, &index); err != nil || index == nil {
+		return 0, false
+	}
+
+	return *index, true
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestImportFields_plain(t *testing.T) {
+	p := &etcdv2ProviderData{}
+
+	f := func(id string) bool {
+		// Any ID without a field name in front is the value itself, including
+		// keys with "=", "," or "@" in them
+		id = "/" + id
+
+		fields, diags := p.importFields(context.Background(), id, "key", "index")
+		return !diags.HasError() && len(fields) == 1 && fields["key"] == id
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestImportFields_composite(t *testing.T) {
+	p := &etcdv2ProviderData{}
+
+	f := func(key string, index uint64, indexFirst bool) bool {
+		key = "/" + strings.NewReplacer(",", "", "=", "").Replace(key)
+
+		parts := []string{"key=" + key, fmt.Sprintf("index=%d", index)}
+		if indexFirst {
+			parts[0], parts[1] = parts[1], parts[0]
+		}
+
+		fields, diags := p.importFields(context.Background(), strings.Join(parts, ","), "key", "index")
+		if diags.HasError() || fields["key"] != key {
+			return false
+		}
+
+		parsed, pinned, diags := parseImportIndex(fields)
+		return !diags.HasError() && pinned && parsed == index
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestImportFields_unknownField(t *testing.T) {
+	p := &etcdv2ProviderData{}
+
+	for _, id := range []string{
+		"key=/app,revision=2",
+		"index=2",
+		"key=",
+		"key=/app,index",
+	} {
+		if _, diags := p.importFields(context.Background(), id, "key", "index"); !diags.HasError() {
+			t.Errorf("import ID %q was accepted", id)
+		}
+	}
+
+	// Fields are only accepted where the resource asks for them
+	if _, diags := p.importFields(context.Background(), "key=/app,index=2", "key"); !diags.HasError() {
+		t.Error("index was accepted without being an optional field")
+	}
+}
+
+func TestParseImportIndex_invalid(t *testing.T) {
+	f := func(value string) bool {
+		value += "x"
+
+		_, _, diags := parseImportIndex(map[string]string{"index": value})
+		return diags.HasError()
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+
+	if _, pinned, diags := parseImportIndex(map[string]string{"key": "/app"}); pinned || diags.HasError() {
+		t.Error("an import ID without an index was pinned")
+	}
+}
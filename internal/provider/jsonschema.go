@@ -0,0 +1,221 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseJSONSchema decodes a JSON Schema document. Only a subset of the
+// keywords is enforced by validateJSON: type, enum, const, properties,
+// required, additionalProperties, items, minItems, maxItems, minimum,
+// maximum, minLength, maxLength and pattern. Other keywords are ignored.
+func parseJSONSchema(raw string) (interface{}, error) {
+	var schema interface{}
+	if err := json.Unmarshal([]byte(raw), &schema); err != nil {
+		return nil, fmt.Errorf("the schema is not valid JSON: %w", err)
+	}
+
+	switch schema.(type) {
+	case bool, map[string]interface{}:
+		return schema, nil
+	}
+
+	return nil, fmt.Errorf("the schema must be an object or a boolean")
+}
+
+// validateJSONDocument validates the JSON document against schema and
+// returns every violation found, sorted.
+func validateJSONDocument(schema interface{}, document string) []string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(document), &value); err != nil {
+		return []string{"the value is not valid JSON: " + err.Error()}
+	}
+
+	errs := validateJSON(schema, value, "")
+	sort.Strings(errs)
+
+	return errs
+}
+
+// validateJSON validates value against schema, prefixing violations with
+// the JSON pointer of where they occurred.
+func validateJSON(schema, value interface{}, pointer string) []string {
+	var errs []string
+	fail := func(format string, args ...interface{}) {
+		location := pointer
+		if location == "" {
+			location = "/"
+		}
+		errs = append(errs, location+": "+fmt.Sprintf(format, args...))
+	}
+
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		if schema == false {
+			fail("no value is allowed")
+		}
+		return errs
+	}
+
+	if t, ok := s["type"]; ok && !matchesJSONType(t, value) {
+		fail("expected %s, got %s", describeJSONType(t), jsonTypeOf(value))
+		return errs
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("the value is not one of the allowed values")
+		}
+	}
+
+	if c, ok := s["const"]; ok && !reflect.DeepEqual(c, value) {
+		fail("the value does not equal the required constant")
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if required, ok := s["required"].([]interface{}); ok {
+			for _, name := range required {
+				if name, ok := name.(string); ok {
+					if _, present := v[name]; !present {
+						fail("missing required property %q", name)
+					}
+				}
+			}
+		}
+
+		properties, _ := s["properties"].(map[string]interface{})
+		for name, child := range v {
+			childPointer := pointer + "/" + escapeJSONPointer(name)
+
+			if propertySchema, ok := properties[name]; ok {
+				errs = append(errs, validateJSON(propertySchema, child, childPointer)...)
+				continue
+			}
+
+			if additional, ok := s["additionalProperties"]; ok {
+				if additional == false {
+					fail("property %q is not allowed", name)
+					continue
+				}
+				errs = append(errs, validateJSON(additional, child, childPointer)...)
+			}
+		}
+
+	case []interface{}:
+		if lower, ok := s["minItems"].(float64); ok && float64(len(v)) < lower {
+			fail("expected at least %v items, got %d", lower, len(v))
+		}
+		if upper, ok := s["maxItems"].(float64); ok && float64(len(v)) > upper {
+			fail("expected at most %v items, got %d", upper, len(v))
+		}
+		if items, ok := s["items"]; ok {
+			for i, child := range v {
+				errs = append(errs, validateJSON(items, child, fmt.Sprintf("%s/%d", pointer, i))...)
+			}
+		}
+
+	case float64:
+		if lower, ok := s["minimum"].(float64); ok && v < lower {
+			fail("%v is less than the minimum of %v", v, lower)
+		}
+		if upper, ok := s["maximum"].(float64); ok && v > upper {
+			fail("%v is greater than the maximum of %v", v, upper)
+		}
+
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if lower, ok := s["minLength"].(float64); ok && length < lower {
+			fail("expected at least %v characters, got %v", lower, length)
+		}
+		if upper, ok := s["maxLength"].(float64); ok && length > upper {
+			fail("expected at most %v characters, got %v", upper, length)
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fail("the schema pattern %q is invalid: %s", pattern, err)
+			} else if !re.MatchString(v) {
+				fail("%q does not match the pattern %q", v, pattern)
+			}
+		}
+	}
+
+	return errs
+}
+
+// matchesJSONType reports whether value is of the type, or one of the types,
+// named by t.
+func matchesJSONType(t, value interface{}) bool {
+	switch t := t.(type) {
+	case string:
+		actual := jsonTypeOf(value)
+		if t == "number" && actual == "integer" {
+			return true
+		}
+		return t == actual
+	case []interface{}:
+		for _, option := range t {
+			if matchesJSONType(option, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func describeJSONType(t interface{}) string {
+	if options, ok := t.([]interface{}); ok {
+		names := make([]string, 0, len(options))
+		for _, name := range options {
+			names = append(names, fmt.Sprint(name))
+		}
+		return strings.Join(names, " or ")
+	}
+
+	return fmt.Sprint(t)
+}
+
+// jsonTypeOf returns the JSON Schema type name of a decoded JSON value.
+// Numbers without a fractional part are integers.
+func jsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return "unknown"
+}
+
+// escapeJSONPointer escapes a property name for use in a JSON pointer.
+func escapeJSONPointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &keyAccessDataSource{}
+	_ datasource.DataSourceWithConfigure = &keyAccessDataSource{}
+)
+
+var keyAccessRoleAttrTypes = map[string]attr.Type{
+	"role":  types.StringType,
+	"read":  types.BoolType,
+	"write": types.BoolType,
+}
+
+func NewKeyAccessDataSource() datasource.DataSource {
+	return &keyAccessDataSource{}
+}
+
+type keyAccessDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type keyAccessDataSourceModel struct {
+	Key   types.String `tfsdk:"key"`
+	Roles types.List   `tfsdk:"roles"`
+}
+
+func (d *keyAccessDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key_access"
+}
+
+func (d *keyAccessDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the roles that currently have read or write access to a key, " +
+			"so the blast radius of permission changes can be reviewed in the plan.",
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The key to check access for (e.g. '/foo/bar')",
+				Required:            true,
+			},
+			"roles": schema.ListNestedAttribute{
+				MarkdownDescription: "The roles with access to the key",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"role": schema.StringAttribute{
+							MarkdownDescription: "The role name",
+							Computed:            true,
+						},
+						"read": schema.BoolAttribute{
+							MarkdownDescription: "Whether the role can read the key",
+							Computed:            true,
+						},
+						"write": schema.BoolAttribute{
+							MarkdownDescription: "Whether the role can write the key",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *keyAccessDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data keyAccessDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := clientv2.New(d.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+
+		return
+	}
+
+	rApi := clientv2.NewAuthRoleAPI(client)
+
+	access, err := rolesWithAccess(ctx, rApi, data.Key.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd role permissions",
+			errorDetail(err),
+		)
+		return
+	}
+
+	roles := make([]attr.Value, 0, len(access))
+	for _, a := range access {
+		roles = append(roles, types.ObjectValueMust(keyAccessRoleAttrTypes, map[string]attr.Value{
+			"role":  types.StringValue(a.Role),
+			"read":  types.BoolValue(a.Read),
+			"write": types.BoolValue(a.Write),
+		}))
+	}
+
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: keyAccessRoleAttrTypes}, roles)
+	resp.Diagnostics.Append(diags...)
+	data.Roles = list
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *keyAccessDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource = &keyNameDataSource{}
+)
+
+func NewKeyNameDataSource() datasource.DataSource {
+	return &keyNameDataSource{}
+}
+
+type keyNameDataSource struct{}
+
+type keyNameDataSourceModel struct {
+	Key  types.String `tfsdk:"key"`
+	Name types.String `tfsdk:"name"`
+}
+
+func (d *keyNameDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key_name"
+}
+
+func (d *keyNameDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Translates between a key and a name without `/` in it, e.g. for `for_each` keys generated from keys. " +
+			"The name is the key without its leading `/`, with each `/` replaced by `__`. Set exactly one of `key` and `name`. " +
+			"Nothing is read from etcd.",
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The key to translate to a name (e.g. '/foo/bar'), or the key translated from `name`",
+				Optional:            true,
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name to translate to a key (e.g. 'foo__bar'), or the name translated from `key`",
+				Optional:            true,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *keyNameDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data keyNameDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Key.IsNull() == data.Name.IsNull() {
+		resp.Diagnostics.AddError(
+			"Invalid etcd key name",
+			"Set exactly one of key and name.",
+		)
+		return
+	}
+
+	if data.Name.IsNull() {
+		name, err := keyToName(data.Key.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("key"),
+				"Invalid etcd key name",
+				err.Error(),
+			)
+			return
+		}
+		data.Name = types.StringValue(name)
+	} else {
+		data.Key = types.StringValue(nameToKey(data.Name.ValueString()))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// keyToName returns the name of key, with each "/" replaced by "__". Keys
+// whose name would translate back to another key, such as those with "__" in
+// them, are rejected.
+func keyToName(key string) (string, error) {
+	name := strings.ReplaceAll(strings.Trim(key, "/"), "/", "__")
+
+	if nameToKey(name) != "/"+strings.Trim(key, "/") {
+		return "", fmt.Errorf("the key %s has no name that translates back to it, as \"__\" or \"_\" next to a \"/\" would be read as a separator", key)
+	}
+
+	return name, nil
+}
+
+// nameToKey returns the key named name, the reverse of keyToName.
+func nameToKey(name string) string {
+	return "/" + strings.ReplaceAll(name, "__", "/")
+}
@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"terraform-provider-etcdv2/internal/testetcd"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccKeyNameDataSource(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: etcd.ProviderConfig() + `
+data "etcdv2_key_name" "from_key" {
+  key = "/app/config/log_level"
+}
+
+data "etcdv2_key_name" "from_name" {
+  name = data.etcdv2_key_name.from_key.name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.etcdv2_key_name.from_key", "name", "app__config__log_level"),
+					resource.TestCheckResourceAttr("data.etcdv2_key_name.from_name", "key", "/app/config/log_level"),
+				),
+			},
+			// A name with "__" in a segment would translate to another key
+			{
+				Config: etcd.ProviderConfig() + `
+data "etcdv2_key_name" "test" {
+  key = "/app/log__level"
+}
+`,
+				ExpectError: regexp.MustCompile(`no name that translates back`),
+			},
+		},
+	})
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource = &keyURLDataSource{}
+)
+
+func NewKeyURLDataSource() datasource.DataSource {
+	return &keyURLDataSource{}
+}
+
+type keyURLDataSource struct{}
+
+type keyURLDataSourceModel struct {
+	Endpoint types.String `tfsdk:"endpoint"`
+	Key      types.String `tfsdk:"key"`
+	URL      types.String `tfsdk:"url"`
+}
+
+func (d *keyURLDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key_url"
+}
+
+func (d *keyURLDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Builds the full `/v2/keys/...` URL of a key, e.g. for health checks or curl based readiness probes. " +
+			"Nothing is read from etcd.",
+		Attributes: map[string]schema.Attribute{
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "The etcd endpoint, including any gateway path prefix (e.g. 'https://etcd.example.com:2379')",
+				Required:            true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The key to build the URL of (e.g. '/foo/bar')",
+				Required:            true,
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the key. Path segments are escaped where needed",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *keyURLDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data keyURLDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	u, err := keyURL(data.Endpoint.ValueString(), data.Key.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Invalid etcd endpoint",
+			err.Error(),
+		)
+		return
+	}
+	data.URL = types.StringValue(u)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// keyPolicy restricts the keys resources and data sources may touch, so a
+// constrained provider configuration can be handed to tenant modules.
+type keyPolicy struct {
+	allowed []string
+	denied  []string
+}
+
+// check fails if key is outside every allowed prefix or beneath a denied one.
+// Prefixes match whole path segments, so /app does not match /apple.
+func (k *keyPolicy) check(key string) error {
+	for _, prefix := range k.denied {
+		if hasKeyPrefix(key, prefix) {
+			return fmt.Errorf("key %s is beneath the denied prefix %s", key, prefix)
+		}
+	}
+
+	if len(k.allowed) == 0 {
+		return nil
+	}
+
+	for _, prefix := range k.allowed {
+		if hasKeyPrefix(key, prefix) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("key %s is outside the allowed prefixes %s", key, strings.Join(k.allowed, ", "))
+}
+
+func hasKeyPrefix(key, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	return prefix == "" || key == prefix || strings.HasPrefix(key, prefix+"/")
+}
+
+// checkKeyPolicy reports an error on attribute when key violates the key
+// policy. Unknown keys are checked once they are known.
+func (p *etcdv2ProviderData) checkKeyPolicy(attribute string, key types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if p == nil || p.keyPolicy == nil || key.IsUnknown() || key.IsNull() {
+		return diags
+	}
+
+	if err := p.keyPolicy.check(key.ValueString()); err != nil {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Key not permitted by provider policy",
+			"The provider configuration restricts the keys that may be managed: "+err.Error(),
+		)
+	}
+
+	return diags
+}
+
+// checkPlannedKeys applies the key policy and write permission check to the
+// named attributes of a plan, so violations fail at plan time rather than part
+// way through an apply.
+func (p *etcdv2ProviderData) checkPlannedKeys(ctx context.Context, plan tfsdk.Plan, attributes ...string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Nothing to check on destroy
+	if plan.Raw.IsNull() {
+		return diags
+	}
+
+	// Write permissions are those of the cluster the resource targets
+	cluster := p
+	if _, d := plan.Schema.TypeAtPath(ctx, path.Root("cluster")); p != nil && p.writeAccess != nil && !d.HasError() {
+		var name types.String
+		diags.Append(plan.GetAttribute(ctx, path.Root("cluster"), &name)...)
+		if !name.IsUnknown() {
+			cluster, d = p.forCluster(name)
+			diags.Append(d...)
+		}
+	}
+
+	for _, attribute := range attributes {
+		var key types.String
+		diags.Append(plan.GetAttribute(ctx, path.Root(attribute), &key)...)
+		diags.Append(p.checkKeyPolicy(attribute, key)...)
+
+		// Keys are written beneath a prefix, not the prefix itself
+		if attribute == "prefix" && !key.IsNull() && !key.IsUnknown() {
+			key = types.StringValue(strings.TrimSuffix(key.ValueString(), "/") + "/")
+		}
+		diags.Append(cluster.checkWriteAccess(ctx, attribute, key)...)
+	}
+
+	return diags
+}
+
+// isSensitiveKey reports whether key is beneath one of sensitive_key_prefixes.
+func (p *etcdv2ProviderData) isSensitiveKey(key string) bool {
+	if p == nil {
+		return false
+	}
+
+	for _, prefix := range p.sensitivePrefixes {
+		if hasKeyPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sensitiveValueError reports that a data source would expose the value of a
+// key beneath sensitive_key_prefixes in an attribute that is not sensitive.
+func sensitiveValueError(attribute, key string) diag.Diagnostic {
+	return diag.NewAttributeErrorDiagnostic(
+		path.Root(attribute),
+		"Sensitive etcd value",
+		"The value of "+key+" is beneath sensitive_key_prefixes and would be exposed in plan output, "+
+			"so it is not returned by this data source.",
+	)
+}
+
+// checkPermissionPolicy fails if a role permission on key would grant access
+// outside allowed_permission_prefixes. A trailing wildcard is matched as the
+// prefix it grants, so /app/* is beneath /app but /ap* is not.
+func (p *etcdv2ProviderData) checkPermissionPolicy(key string) error {
+	if p == nil || p.permissionPolicy == nil {
+		return nil
+	}
+
+	return p.permissionPolicy.check(strings.TrimSuffix(key, "*"))
+}
+
+// intersect returns the keys within the allowed prefixes that a permission on
+// key covers. A wildcard broader than an allowed prefix is narrowed to that
+// prefix, so /* becomes /app and /app/* when only /app is allowed.
+func (k *keyPolicy) intersect(key string) []string {
+	if k == nil || len(k.allowed) == 0 {
+		return []string{key}
+	}
+
+	trimmed := strings.TrimSuffix(key, "*")
+	wildcard := trimmed != key
+
+	var keys []string
+	for _, prefix := range k.allowed {
+		prefix = strings.TrimSuffix(prefix, "/")
+
+		switch {
+		case hasKeyPrefix(trimmed, prefix):
+			return []string{key}
+		case wildcard && strings.HasPrefix(prefix, trimmed):
+			keys = append(keys, prefix, prefix+"/*")
+		}
+	}
+
+	return keys
+}
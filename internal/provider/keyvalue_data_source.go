@@ -2,11 +2,15 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	clientv2 "go.etcd.io/etcd/client/v2"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -15,20 +19,48 @@ var (
 	_ datasource.DataSourceWithConfigure = &keyValueDataSource{}
 )
 
+var keyValueNodeAttrTypes = map[string]attr.Type{
+	"created_index":  types.Int64Type,
+	"modified_index": types.Int64Type,
+	"ttl":            types.Int64Type,
+	"expiration":     types.StringType,
+	"dir":            types.BoolType,
+}
+
 func NewKeyValueDataSource() datasource.DataSource {
 	return &keyValueDataSource{}
 }
 
 type keyValueDataSource struct {
-	cfg *clientv2.Config
+	provider *etcdv2ProviderData
 }
 
 type keyValueDataSourceModel struct {
-	Key           types.String `tfsdk:"key"`
-	Value         types.String `tfsdk:"value"`
-	ModifiedIndex types.Int64  `tfsdk:"modified_index"`
+	Key            types.String `tfsdk:"key"`
+	Value          types.String `tfsdk:"value"`
+	SensitiveValue types.String `tfsdk:"sensitive_value"`
+	ModifiedIndex  types.Int64  `tfsdk:"modified_index"`
+	IsDir          types.Bool   `tfsdk:"is_dir"`
+	ValueSHA256    types.String `tfsdk:"value_sha256"`
+	ValueMD5       types.String `tfsdk:"value_md5"`
+	Node           types.Object `tfsdk:"node"`
+
+	EmptyAsMissing types.Bool `tfsdk:"empty_as_missing"`
+
+	WaitForExists types.Bool  `tfsdk:"wait_for_exists"`
+	WaitTimeout   types.Int64 `tfsdk:"wait_timeout"`
+
+	Decode types.String `tfsdk:"decode"`
+
+	Cluster types.String `tfsdk:"cluster"`
+
+	EtcdIndex types.Int64 `tfsdk:"etcd_index"`
+	RaftIndex types.Int64 `tfsdk:"raft_index"`
 }
 
+// defaultWaitTimeout is used when wait_timeout is not configured.
+const defaultWaitTimeout = 300 * time.Second
+
 func (d *keyValueDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_keyvalue"
 }
@@ -36,6 +68,10 @@ func (d *keyValueDataSource) Metadata(_ context.Context, req datasource.Metadata
 func (d *keyValueDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"cluster": schema.StringAttribute{
+				MarkdownDescription: "The name of a cluster in the provider's `endpoints_by_name` to read from instead of `host`",
+				Optional:            true,
+			},
 			"key": schema.StringAttribute{
 				Required: true,
 				Computed: false,
@@ -43,9 +79,79 @@ func (d *keyValueDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 			"value": schema.StringAttribute{
 				Computed: true,
 			},
+			"sensitive_value": schema.StringAttribute{
+				MarkdownDescription: "The value of a key beneath the provider's `sensitive_key_prefixes`, for which `value` and the checksums are null",
+				Computed:            true,
+				Sensitive:           true,
+			},
 			"modified_index": schema.Int64Attribute{
 				Computed: true,
 			},
+			"value_sha256": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded SHA-256 checksum of the value",
+				Computed:            true,
+			},
+			"value_md5": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded MD5 checksum of the value",
+				Computed:            true,
+			},
+			"is_dir": schema.BoolAttribute{
+				MarkdownDescription: "Whether the key is a directory. The value of a directory is null",
+				Computed:            true,
+			},
+			"node": schema.SingleNestedAttribute{
+				MarkdownDescription: "The full metadata of the node",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"created_index": schema.Int64Attribute{
+						MarkdownDescription: "The index at which the node was created",
+						Computed:            true,
+					},
+					"modified_index": schema.Int64Attribute{
+						MarkdownDescription: "The index at which the node was last modified",
+						Computed:            true,
+					},
+					"ttl": schema.Int64Attribute{
+						MarkdownDescription: "The remaining time to live of the node in seconds, or null if it does not expire",
+						Computed:            true,
+					},
+					"expiration": schema.StringAttribute{
+						MarkdownDescription: "The RFC3339 time at which the node expires, or null if it does not expire",
+						Computed:            true,
+					},
+					"dir": schema.BoolAttribute{
+						MarkdownDescription: "Whether the node is a directory",
+						Computed:            true,
+					},
+				},
+			},
+			"empty_as_missing": schema.BoolAttribute{
+				MarkdownDescription: "Treat a key that exists with an empty value as absent, failing the read",
+				Optional:            true,
+			},
+			"decode": schema.StringAttribute{
+				MarkdownDescription: "Decode values stored encoded by other tools, one of `none`, `base64`, `gzip+base64` or `auto`. " +
+					"`auto` decodes base64 values that hold gzip data or UTF-8 text and leaves other values as they are. Defaults to `none`",
+				Optional: true,
+			},
+			"wait_for_exists": schema.BoolAttribute{
+				MarkdownDescription: "Block until the key exists instead of failing the read, watching for it to be created, " +
+					"e.g. when the value is published asynchronously by a bootstrapping machine",
+				Optional: true,
+			},
+			"wait_timeout": schema.Int64Attribute{
+				MarkdownDescription: "How long in seconds `wait_for_exists` waits for the key before failing. Defaults to 300",
+				Optional:            true,
+			},
+			"etcd_index": schema.Int64Attribute{
+				MarkdownDescription: "The etcd index of the cluster when the key was read. Reads of other keys can be compared " +
+					"against it to check they were taken at a consistent point",
+				Computed: true,
+			},
+			"raft_index": schema.Int64Attribute{
+				MarkdownDescription: "The raft index of the cluster when the key was read",
+				Computed:            true,
+			},
 		},
 	}
 }
@@ -57,7 +163,20 @@ func (d *keyValueDataSource) Read(ctx context.Context, req datasource.ReadReques
 	//var kApi clientv2.httpKeysAPI
 	//kApi = *d.kApi
 
-	client, err := clientv2.New(*d.cfg)
+	resp.Diagnostics.Append(d.provider.checkKeyPolicy("key", data.Key)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := d.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	kApi, recorder, err := provider.recordingKeysAPI()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create etcdv2 API client",
@@ -68,27 +187,86 @@ func (d *keyValueDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
-	kApi := clientv2.NewKeysAPI(client)
-
-	keyvalue, err := kApi.Get(context.Background(), data.Key.ValueString(), nil)
+	var keyvalue *clientv2.Response
+	if data.WaitForExists.ValueBool() {
+		timeout := defaultWaitTimeout
+		if !data.WaitTimeout.IsNull() {
+			timeout = time.Duration(data.WaitTimeout.ValueInt64()) * time.Second
+		}
+		keyvalue, err = waitForKey(ctx, kApi, data.Key.ValueString(), timeout)
+	} else if err = provider.missing.lookup(data.Key.ValueString()); err == nil {
+		keyvalue, err = kApi.Get(context.Background(), data.Key.ValueString(), nil)
+	}
 	if err != nil {
+		if provider.missing.record(data.Key.ValueString(), err) {
+			resp.Diagnostics.Append(provider.missing.Diagnostic(data.Key.ValueString()))
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd keyvalue",
+			errorDetail(err),
+		)
+		return
+	}
+
+	if data.EmptyAsMissing.ValueBool() && !keyvalue.Node.Dir && keyvalue.Node.Value == "" {
 		resp.Diagnostics.AddError(
 			"Unable to Read etcd keyvalue",
-			err.Error(),
+			"The key "+data.Key.ValueString()+" has an empty value, which empty_as_missing treats as absent",
 		)
 		return
 	}
 
-	data.Value = types.StringValue(keyvalue.Node.Value)
+	headers := recorder.headers()
+	data.EtcdIndex = types.Int64Value(int64(keyvalue.Index))
+	data.RaftIndex = types.Int64Value(int64(headers.RaftIndex))
+
+	data.IsDir = types.BoolValue(keyvalue.Node.Dir)
 	data.ModifiedIndex = types.Int64Value(int64(keyvalue.Node.ModifiedIndex))
 
+	if keyvalue.Node.Dir {
+		data.Value = types.StringNull()
+	} else {
+		value, err := decodeValue(data.Decode.ValueString(), keyvalue.Node.Value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("decode"),
+				"Unable to decode etcd keyvalue",
+				"The value of "+data.Key.ValueString()+" could not be decoded: "+err.Error(),
+			)
+			return
+		}
+		data.Value = types.StringValue(value)
+	}
+	data.SensitiveValue = types.StringNull()
+	if provider.isSensitiveKey(keyvalue.Node.Key) {
+		data.SensitiveValue, data.Value = data.Value, types.StringNull()
+	}
+	data.ValueSHA256, data.ValueMD5 = valueChecksums(data.Value)
+
+	ttl, expiration := types.Int64Null(), types.StringNull()
+	if keyvalue.Node.Expiration != nil {
+		ttl = types.Int64Value(keyvalue.Node.TTL)
+		expiration = types.StringValue(keyvalue.Node.Expiration.Format(time.RFC3339))
+	}
+
+	node, diags := types.ObjectValue(keyValueNodeAttrTypes, map[string]attr.Value{
+		"created_index":  types.Int64Value(int64(keyvalue.Node.CreatedIndex)),
+		"modified_index": types.Int64Value(int64(keyvalue.Node.ModifiedIndex)),
+		"ttl":            ttl,
+		"expiration":     expiration,
+		"dir":            types.BoolValue(keyvalue.Node.Dir),
+	})
+	resp.Diagnostics.Append(diags...)
+	data.Node = node
+
 	//keyValueState := keyValueModel{
 	//	Key:         types.StringValue(keyvalue.Node.Key),
 	//	Value:       types.StringValue(keyvalue.Node.Value),
 	//	LastUpdated: types.StringValue(string(keyvalue.Node.ModifiedIndex)),
 	//}
 
-	diags := resp.State.Set(ctx, &data)
+	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -96,12 +274,45 @@ func (d *keyValueDataSource) Read(ctx context.Context, req datasource.ReadReques
 
 }
 
+// waitForKey reads key, watching for it to be created while it does not exist
+// yet. The watch starts at the index of the failed read, so a key created in
+// between is not missed.
+func waitForKey(ctx context.Context, kApi clientv2.KeysAPI, key string, timeout time.Duration) (*clientv2.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		resp, err := kApi.Get(ctx, key, nil)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("key %s did not appear within %s", key, timeout)
+		}
+
+		clientErr, ok := err.(clientv2.Error)
+		if !ok || clientErr.Code != clientv2.ErrorCodeKeyNotFound {
+			return resp, err
+		}
+
+		watcher := kApi.Watcher(key, &clientv2.WatcherOptions{AfterIndex: clientErr.Index})
+		if _, err := watcher.Next(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("key %s did not appear within %s", key, timeout)
+			}
+
+			// Events older than the watch index were compacted, so read again
+			if clientErr, ok := err.(clientv2.Error); ok && clientErr.Code == clientv2.ErrorCodeEventIndexCleared {
+				continue
+			}
+			return nil, err
+		}
+	}
+}
+
 func (d *keyValueDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	cfg := req.ProviderData.(*clientv2.Config)
+	provider := req.ProviderData.(*etcdv2ProviderData)
 
-	d.cfg = cfg
+	d.provider = provider
 }
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/base64"
+	"math/rand"
+	"regexp"
+	"testing"
+	"time"
+
+	"terraform-provider-etcdv2/internal/testetcd"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccKeyvalueDataSource_decode reads random values stored base64 and
+// gzip+base64 encoded, as tools such as confd write them.
+func TestAccKeyvalueDataSource_decode(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	seed := time.Now().UnixNano()
+	t.Logf("seed %d", seed)
+	rnd := rand.New(rand.NewSource(seed))
+
+	plain := randomValue(rnd)
+	compressed := randomValue(rnd)
+	etcd.Set("/encoded/base64", base64.StdEncoding.EncodeToString([]byte(plain)))
+	etcd.Set("/encoded/gzip", gzipBase64(t, compressed, 9))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: etcd.ProviderConfig() + `
+data "etcdv2_keyvalue" "base64" {
+  key    = "/encoded/base64"
+  decode = "base64"
+}
+
+data "etcdv2_keyvalue" "gzip" {
+  key    = "/encoded/gzip"
+  decode = "auto"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.etcdv2_keyvalue.base64", "value", plain),
+					resource.TestCheckResourceAttr("data.etcdv2_keyvalue.gzip", "value", compressed),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyvalueDataSource_missing(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: etcd.ProviderConfig() + `
+data "etcdv2_keyvalue" "a" {
+  key = "/app/a"
+}
+
+data "etcdv2_keyvalue" "b" {
+  key = "/app/b"
+}
+`,
+				ExpectError: regexp.MustCompile(`The key /app/b read by this data source does not\s+exist`),
+			},
+		},
+	})
+}
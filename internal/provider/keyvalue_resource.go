@@ -2,18 +2,34 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	clientv2 "go.etcd.io/etcd/client/v2"
 
+	"terraform-provider-etcdv2/pkg/etcdv2ops"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
-	_ resource.Resource              = &KeyValueResource{}
-	_ resource.ResourceWithConfigure = &KeyValueResource{}
+	_ resource.Resource                = &KeyValueResource{}
+	_ resource.ResourceWithConfigure   = &KeyValueResource{}
+	_ resource.ResourceWithImportState = &KeyValueResource{}
+
+	_ resource.ResourceWithValidateConfig = &KeyValueResource{}
+	_ resource.ResourceWithModifyPlan     = &KeyValueResource{}
 )
 
 func NewKeyValueResource() resource.Resource {
@@ -22,7 +38,7 @@ func NewKeyValueResource() resource.Resource {
 
 // KeyValueResource defines the resource implementation
 type KeyValueResource struct {
-	cfg *clientv2.Config
+	provider *etcdv2ProviderData
 }
 
 // KeyValueResourceModel describes the resource data model.
@@ -30,6 +46,57 @@ type KeyValueResourceModel struct {
 	Key           types.String `tfsdk:"key"`
 	Value         types.String `tfsdk:"value"`
 	ModifiedIndex types.Int64  `tfsdk:"modified_index"`
+	ValueSHA256   types.String `tfsdk:"value_sha256"`
+	ValueMD5      types.String `tfsdk:"value_md5"`
+
+	PrevValue         types.String `tfsdk:"prev_value"`
+	PrevModifiedIndex types.Int64  `tfsdk:"prev_modified_index"`
+
+	EmptyAsMissing     types.Bool `tfsdk:"empty_as_missing"`
+	CreateOnlyIfAbsent types.Bool `tfsdk:"create_only_if_absent"`
+	Adopted            types.Bool `tfsdk:"adopted"`
+	AdoptExisting      types.Bool `tfsdk:"adopt_existing"`
+
+	RequireExistingParent types.Bool `tfsdk:"require_existing_parent"`
+
+	AbortOnConcurrentChange types.Bool `tfsdk:"abort_on_concurrent_change"`
+	TrackModifiedIndex      types.Bool `tfsdk:"track_modified_index"`
+
+	RequireQuorumMembers types.Int64 `tfsdk:"require_quorum_members"`
+
+	RefreshInterval types.Int64 `tfsdk:"refresh_interval"`
+	DependsOnIndex  types.Int64 `tfsdk:"depends_on_index"`
+
+	ExpiresAt types.String `tfsdk:"expires_at"`
+
+	ShadowHistory    types.Bool  `tfsdk:"shadow_history"`
+	HistoryRetention types.Int64 `tfsdk:"history_retention"`
+
+	Metadata types.Map `tfsdk:"metadata"`
+
+	Type types.String `tfsdk:"type"`
+
+	DefaultValue types.String `tfsdk:"default_value"`
+	WasDefaulted types.Bool   `tfsdk:"was_defaulted"`
+
+	Verify     *keyValueVerifyModel     `tfsdk:"verify"`
+	Validation *keyValueValidationModel `tfsdk:"validation"`
+
+	Cluster types.String `tfsdk:"cluster"`
+
+	RespectChangeWindow types.Bool `tfsdk:"respect_change_window"`
+}
+
+// keyValueValidationModel describes the rules a configured value must follow.
+type keyValueValidationModel struct {
+	Regex      types.String `tfsdk:"regex"`
+	JSONSchema types.String `tfsdk:"json_schema"`
+}
+
+// keyValueVerifyModel describes what the key must look like once written.
+type keyValueVerifyModel struct {
+	ExpectValueRegex types.String `tfsdk:"expect_value_regex"`
+	ExpectTTLMin     types.Int64  `tfsdk:"expect_ttl_min"`
 }
 
 func (r *KeyValueResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -38,35 +105,293 @@ func (r *KeyValueResource) Metadata(ctx context.Context, req resource.MetadataRe
 
 func (r *KeyValueResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "etcdv2 Key-value resource",
+		MarkdownDescription: "etcdv2 Key-value resource. Planning fails if another resource writes the same key, except for two new resources configured identically, which cannot be told apart.",
 		Attributes: map[string]schema.Attribute{
+			"cluster": schema.StringAttribute{
+				MarkdownDescription: "The name of a cluster in the provider's `endpoints_by_name` to manage the key in instead of `host`. Changing it recreates the resource",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"respect_change_window": schema.BoolAttribute{
+				MarkdownDescription: "Fail to create, update or delete the key outside of the provider's `change_window_cron`",
+				Optional:            true,
+			},
 			"key": schema.StringAttribute{
-				MarkdownDescription: "The unique location of this resource (e.g. '/foo/bar')",
+				MarkdownDescription: "The unique location of this resource (e.g. '/foo/bar'). Changing the key renames the existing value rather than recreating it",
 				Required:            true,
 				Computed:            false,
 			},
 			"value": schema.StringAttribute{
-				MarkdownDescription: "The data stored in this resource",
-				Required:            true,
+				MarkdownDescription: "The data stored in this resource. Exactly one of `value` and `default_value` must be set",
+				Optional:            true,
 				Computed:            false,
 			},
+			"default_value": schema.StringAttribute{
+				MarkdownDescription: "A value only written if the key does not exist when the resource is created. " +
+					"It never overwrites the key afterwards, so the key is left to be changed outside of Terraform",
+				Optional: true,
+			},
+			"was_defaulted": schema.BoolAttribute{
+				MarkdownDescription: "Whether `default_value` was written because the key did not exist at create",
+				Computed:            true,
+			},
 			"modified_index": schema.Int64Attribute{
-				MarkdownDescription: "The index at which this resource was last modified",
+				MarkdownDescription: "The index at which this resource was last modified. Null when `track_modified_index` is false",
+				Computed:            true,
+			},
+			"require_quorum_members": schema.Int64Attribute{
+				MarkdownDescription: "Refuse updates and deletes unless at least this many cluster members report healthy, " +
+					"so the key is never modified while the cluster is degraded",
+				Optional: true,
+			},
+			"track_modified_index": schema.BoolAttribute{
+				MarkdownDescription: "Track `modified_index` in state. Disable it to keep unrelated writes to the key from showing up as refresh diffs. " +
+					"Renames are then no longer conditional on the old key being unchanged. Conflicts with `abort_on_concurrent_change`. Defaults to true",
+				Optional: true,
+			},
+			"value_sha256": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded SHA-256 checksum of the value in etcd",
+				Computed:            true,
+			},
+			"value_md5": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded MD5 checksum of the value in etcd",
+				Computed:            true,
+			},
+			"empty_as_missing": schema.BoolAttribute{
+				MarkdownDescription: "Treat a key that exists with an empty value as absent. Such a key is recreated with the configured value",
+				Optional:            true,
+			},
+			"create_only_if_absent": schema.BoolAttribute{
+				MarkdownDescription: "Only write `value` if the key does not exist yet. An existing key is adopted untouched, " +
+					"and later changes made in etcd are never overwritten unless `value` itself changes",
+				Optional: true,
+			},
+			"require_existing_parent": schema.BoolAttribute{
+				MarkdownDescription: "Fail instead of implicitly creating missing parent directories, so namespaces must be created explicitly, e.g. with `etcdv2_directory`",
+				Optional:            true,
+			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Adopt a key that already exists instead of failing, overwriting it with `value` as an update would",
+				Optional:            true,
+			},
+			"abort_on_concurrent_change": schema.BoolAttribute{
+				MarkdownDescription: "Abort updates and deletes if the key was modified since it was last read during plan, " +
+					"by making the write conditional on `modified_index`",
+				Optional: true,
+			},
+			"depends_on_index": schema.Int64Attribute{
+				MarkdownDescription: "Only write the key once the cluster has reached this index, typically another resource's `modified_index`. " +
+					"Writes fail instead of being applied ahead of the write they depend on",
+				Optional: true,
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "An RFC3339 timestamp at which the key expires. The TTL is computed from it on every apply, " +
+					"and an expired key is removed from state",
+				Optional: true,
+			},
+			"shadow_history": schema.BoolAttribute{
+				MarkdownDescription: "Copy the current value to the hidden directory `_<name>_history` next to the key before each update, " +
+					"keyed by its modified index. The history is kept when the key is destroyed",
+				Optional: true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of `value`, one of `string`, `int`, `bool` or `float`. Values of the other types must be written " +
+					"in canonical form, i.e. base-10 integers, `true` or `false`, and the shortest representation of a float, " +
+					"so applications reading the key never see `1` and `True` used interchangeably. Defaults to `string`",
+				Optional: true,
+			},
+			"metadata": schema.MapAttribute{
+				MarkdownDescription: "Annotations such as `owner` and `description`, stored as JSON in the hidden sibling key `<dir>/_meta/<name>` " +
+					"together with `managed-by = \"terraform\"`, so etcd browsers can tell the key is managed by Terraform",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"history_retention": schema.Int64Attribute{
+				MarkdownDescription: "How many previous values `shadow_history` keeps. Defaults to 10",
+				Optional:            true,
+			},
+			"validation": schema.SingleNestedAttribute{
+				MarkdownDescription: "Rules the configured `value` or `default_value` must follow, so malformed values never reach the consumers of the key",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"regex": schema.StringAttribute{
+						MarkdownDescription: "A regular expression the value must match, checked at plan time",
+						Optional:            true,
+					},
+					"json_schema": schema.StringAttribute{
+						MarkdownDescription: "A JSON Schema the value must be a valid JSON document for, checked at apply time before the key is written. " +
+							"The keywords `type`, `enum`, `const`, `properties`, `required`, `additionalProperties`, `items`, `minItems`, `maxItems`, " +
+							"`minimum`, `maximum`, `minLength`, `maxLength` and `pattern` are enforced, others are ignored",
+						Optional: true,
+					},
+				},
+			},
+			"verify": schema.SingleNestedAttribute{
+				MarkdownDescription: "Expectations checked against the key right after it is created or updated, failing the apply if they are not met. " +
+					"The resource is kept in state, so a failed create is tainted",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"expect_value_regex": schema.StringAttribute{
+						MarkdownDescription: "A regular expression the stored value must match",
+						Optional:            true,
+					},
+					"expect_ttl_min": schema.Int64Attribute{
+						MarkdownDescription: "The minimum TTL in seconds the key must have. Keys that do not expire fail the check",
+						Optional:            true,
+					},
+				},
+			},
+			"refresh_interval": schema.Int64Attribute{
+				MarkdownDescription: "Skip reading the key during refresh if it was last read or written less than this many seconds ago. " +
+					"Changes made outside of Terraform within the interval are not detected",
+				Optional: true,
+			},
+			"adopted": schema.BoolAttribute{
+				MarkdownDescription: "Whether an existing key was adopted by `create_only_if_absent` instead of being written",
+				Computed:            true,
+			},
+			"prev_value": schema.StringAttribute{
+				MarkdownDescription: "The value replaced by the last apply, if any",
+				Computed:            true,
+			},
+			"prev_modified_index": schema.Int64Attribute{
+				MarkdownDescription: "The modified index of the value replaced by the last apply, if any",
 				Computed:            true,
 			},
 		},
 	}
 }
 
+func (r *KeyValueResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data KeyValueResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.AbortOnConcurrentChange.ValueBool() && !data.TrackModifiedIndex.IsNull() && !data.TrackModifiedIndex.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("track_modified_index"),
+			"Invalid etcd keyvalue configuration",
+			"abort_on_concurrent_change relies on modified_index and cannot be combined with track_modified_index = false.",
+		)
+	}
+
+	if data.Verify != nil && !data.Verify.ExpectValueRegex.IsUnknown() && !data.Verify.ExpectValueRegex.IsNull() {
+		if _, err := regexp.Compile(data.Verify.ExpectValueRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("verify").AtName("expect_value_regex"),
+				"Invalid etcd keyvalue verification",
+				"The regular expression could not be compiled: "+err.Error(),
+			)
+		}
+	}
+
+	if data.Validation != nil && !data.Validation.JSONSchema.IsUnknown() && !data.Validation.JSONSchema.IsNull() {
+		if _, err := parseJSONSchema(data.Validation.JSONSchema.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("validation").AtName("json_schema"),
+				"Invalid etcd keyvalue validation",
+				err.Error(),
+			)
+		}
+	}
+
+	if data.Value.IsUnknown() || data.DefaultValue.IsUnknown() {
+		return
+	}
+
+	if data.Value.IsNull() == data.DefaultValue.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("value"),
+			"Invalid etcd keyvalue value",
+			"Exactly one of value and default_value must be set.",
+		)
+		return
+	}
+
+	attribute, value := "value", data.Value
+	if !data.DefaultValue.IsNull() {
+		attribute, value = "default_value", data.DefaultValue
+	}
+
+	if data.Validation != nil && !data.Validation.Regex.IsUnknown() && !data.Validation.Regex.IsNull() {
+		re, err := regexp.Compile(data.Validation.Regex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("validation").AtName("regex"),
+				"Invalid etcd keyvalue validation",
+				"The regular expression could not be compiled: "+err.Error(),
+			)
+		} else if !re.MatchString(value.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Invalid etcd keyvalue value",
+				"The value does not match the validation regex "+data.Validation.Regex.ValueString(),
+			)
+		}
+	}
+
+	if data.Type.IsUnknown() || data.Type.IsNull() {
+		return
+	}
+
+	canonical, err := canonicalValue(data.Type.ValueString(), value.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attribute),
+			"Invalid etcd keyvalue value",
+			"The value does not match the declared type: "+err.Error(),
+		)
+		return
+	}
+
+	if canonical != value.ValueString() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attribute),
+			"Invalid etcd keyvalue value",
+			"The value "+value.ValueString()+" is not in canonical "+data.Type.ValueString()+" form, use "+canonical+" instead",
+		)
+	}
+}
+
+func (r *KeyValueResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(r.provider.checkPlannedKeys(ctx, req.Plan, "key")...)
+
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan KeyValueResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(plan.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || plan.Key.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(provider.claimKey(path.Root("key"), plan.Key.ValueString(), claimOwner("etcdv2_keyvalue", "key "+plan.Key.ValueString(), req.Config, req.State))...)
+}
+
 func (r *KeyValueResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured
 	if req.ProviderData == nil {
 		return
 	}
 
-	cfg := req.ProviderData.(*clientv2.Config)
+	provider := req.ProviderData.(*etcdv2ProviderData)
 
-	r.cfg = cfg
+	r.provider = provider
 }
 
 func (r *KeyValueResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -80,8 +405,47 @@ func (r *KeyValueResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(provider.checkChangeWindow(data.RespectChangeWindow)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.ExpiresAt.IsNull() {
+		if _, err := ttlUntil(data.ExpiresAt.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("expires_at"),
+				"Invalid etcd keyvalue expiry",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(data.validateSchema()...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
 	// Create new etcd client from config
-	client, err := clientv2.New(*r.cfg)
+	kApi, err := provider.keysAPI()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create etcdv2 API client",
@@ -91,22 +455,102 @@ func (r *KeyValueResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	// Retrieve KeyAPI from client
-	kApi := clientv2.NewKeysAPI(client)
+	if !data.DependsOnIndex.IsNull() {
+		if err := requireIndex(ctx, kApi, uint64(data.DependsOnIndex.ValueInt64())); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to satisfy etcd keyvalue depends_on_index",
+				errorDetail(err),
+			)
+			return
+		}
+	}
+
+	if data.RequireExistingParent.ValueBool() {
+		if err := requireParent(ctx, kApi, data.Key.ValueString()); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create etcd keyvalue",
+				errorDetail(err),
+			)
+			return
+		}
+	}
+
+	value, keepExisting := data.Value.ValueString(), data.CreateOnlyIfAbsent.ValueBool()
+	if !data.DefaultValue.IsNull() {
+		value, keepExisting = data.DefaultValue.ValueString(), true
+	}
 
-	keyvalue, err := kApi.Create(context.Background(), data.Key.ValueString(), data.Value.ValueString())
+	keyvalue, kept, err := etcdv2ops.EnsureKey(ctx, kApi, data.Key.ValueString(), value, etcdv2ops.EnsureKeyOptions{
+		KeepExisting:   keepExisting,
+		Adopt:          data.AdoptExisting.ValueBool(),
+		EmptyAsMissing: data.EmptyAsMissing.ValueBool(),
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create etcd keyvalue",
-			err.Error(),
+			errorDetail(err),
+		)
+		return
+	}
+
+	if kept {
+		// The configured value only seeds the key, so state keeps it as is
+		data.ModifiedIndex = data.trackedIndex(keyvalue.Node.ModifiedIndex)
+		data.ValueSHA256, data.ValueMD5 = valueChecksums(types.StringValue(keyvalue.Node.Value))
+		data.Adopted = types.BoolValue(true)
+		data.WasDefaulted = types.BoolValue(false)
+		data.setPrevNode(nil)
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		resp.Diagnostics.Append(data.checkExpectations(keyvalue.Node)...)
+		return
+	}
+
+	if !data.ExpiresAt.IsNull() {
+		keyvalue.Node, err = applyExpiry(ctx, kApi, keyvalue.Node, data.ExpiresAt.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to set etcd keyvalue expiry",
+				errorDetail(err),
+			)
+			return
+		}
+	}
+
+	if err := verifyWrite(ctx, kApi, keyvalue.Node); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to verify etcd keyvalue create",
+			"The write was acknowledged but could not be read back from the cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
 		)
 		return
 	}
 
-	data.Value = types.StringValue(keyvalue.Node.Value)
-	data.ModifiedIndex = types.Int64Value(int64(keyvalue.Node.ModifiedIndex))
+	if !data.Metadata.IsNull() {
+		resp.Diagnostics.Append(data.storeMetadata(ctx, kApi)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(provider.recordChange(ctx, changeCreate, keyvalue.Node.Key, prevIndex(keyvalue.PrevNode), keyvalue.Node.ModifiedIndex)...)
+
+	if data.DefaultValue.IsNull() {
+		data.Value = types.StringValue(keyvalue.Node.Value)
+	}
+	data.ModifiedIndex = data.trackedIndex(keyvalue.Node.ModifiedIndex)
+	data.ValueSHA256, data.ValueMD5 = valueChecksums(types.StringValue(keyvalue.Node.Value))
+	data.Adopted = types.BoolValue(false)
+	data.WasDefaulted = types.BoolValue(!data.DefaultValue.IsNull())
+	data.setPrevNode(keyvalue.PrevNode)
+
+	if !data.RefreshInterval.IsNull() {
+		resp.Diagnostics.Append(markRead(ctx, resp.Private)...)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(data.checkExpectations(keyvalue.Node)...)
 }
 
 func (r *KeyValueResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -119,8 +563,20 @@ func (r *KeyValueResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Prior state is kept as is while the key was read recently enough
+	if readWithin(ctx, req.Private, time.Duration(data.RefreshInterval.ValueInt64())*time.Second) {
+		return
+	}
+
 	// Create new etcd client from config
-	client, err := clientv2.New(*r.cfg)
+	kApi, err := provider.keysAPI()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create etcdv2 API client",
@@ -130,36 +586,176 @@ func (r *KeyValueResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	// Retrieve KeyAPI from client
-	kApi := clientv2.NewKeysAPI(client)
-
 	keyvalue, err := kApi.Get(context.Background(), data.Key.ValueString(), nil)
+	if clientv2.IsKeyNotFound(err) && !data.ExpiresAt.IsNull() {
+		// The key has reached expires_at
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read etcd keyvalue",
-			err.Error(),
+			errorDetail(err),
 		)
 		return
 	}
 
-	data.Value = types.StringValue(keyvalue.Node.Value)
-	data.ModifiedIndex = types.Int64Value(int64(keyvalue.Node.ModifiedIndex))
+	if index, ok := importedIndex(ctx, req.Private); ok {
+		if keyvalue.Node.ModifiedIndex != index {
+			resp.Diagnostics.AddError(
+				"Unable to Import etcd keyvalue",
+				fmt.Sprintf("The key %s is at modified index %d, but the import was pinned to index %d. "+
+					"The key changed since the revision to import was chosen.", data.Key.ValueString(), keyvalue.Node.ModifiedIndex, index),
+			)
+			return
+		}
 
-	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		// The pin is only checked by the refresh following the import
+		resp.Diagnostics.Append(clearImportIndex(ctx, resp.Private)...)
+	}
+
+	if !data.RefreshInterval.IsNull() {
+		resp.Diagnostics.Append(markRead(ctx, resp.Private)...)
+	}
+
+	if keyvalue.Node.Dir {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd keyvalue",
+			"The key "+data.Key.ValueString()+" is a directory. Use the etcdv2_directory resource to manage directories",
+		)
+		return
+	}
+
+	if data.EmptyAsMissing.ValueBool() && keyvalue.Node.Value == "" {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	metadata := data.Metadata
+	if !data.Metadata.IsNull() {
+		_, keepMarker := data.Metadata.Elements()[metadataManagedBy]
+
+		stored, err := readMetadata(ctx, kApi, data.Key.ValueString(), keepMarker)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read etcd keyvalue metadata",
+				errorDetail(err),
+			)
+			return
+		}
+
+		if stored == nil {
+			metadata = types.MapNull(types.StringType)
+		} else {
+			var diags diag.Diagnostics
+			metadata, diags = types.MapValueFrom(ctx, types.StringType, stored)
+			resp.Diagnostics.Append(diags...)
+		}
+	}
+
+	// Keys seeded once are not reconciled against the configured value
+	if data.CreateOnlyIfAbsent.ValueBool() || !data.DefaultValue.IsNull() {
+		data.Metadata = metadata
+		data.ModifiedIndex = data.trackedIndex(keyvalue.Node.ModifiedIndex)
+		data.ValueSHA256, data.ValueMD5 = valueChecksums(types.StringValue(keyvalue.Node.Value))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	if provider.drift != nil && data.Value.ValueString() != keyvalue.Node.Value {
+		tflog.Warn(ctx, "etcd keyvalue drifted from state", map[string]interface{}{
+			"key": data.Key.ValueString(),
+		})
+		resp.Diagnostics.Append(provider.drift.recordValue(data.Key.ValueString())...)
+	}
+
+	refreshed := data
+	refreshed.Value = types.StringValue(keyvalue.Node.Value)
+	refreshed.ModifiedIndex = refreshed.trackedIndex(keyvalue.Node.ModifiedIndex)
+	refreshed.ValueSHA256, refreshed.ValueMD5 = valueChecksums(types.StringValue(keyvalue.Node.Value))
+
+	// Only the attributes that drifted are written, so refresh-only plans
+	// attribute each change precisely
+	for _, change := range []struct {
+		name         string
+		prior, value attr.Value
+	}{
+		{"value", data.Value, refreshed.Value},
+		{"modified_index", data.ModifiedIndex, refreshed.ModifiedIndex},
+		{"value_sha256", data.ValueSHA256, refreshed.ValueSHA256},
+		{"value_md5", data.ValueMD5, refreshed.ValueMD5},
+		{"metadata", data.Metadata, metadata},
+	} {
+		if change.prior.Equal(change.value) {
+			continue
+		}
+
+		logDrift(ctx, data.Key.ValueString(), change.name, change.prior, change.value)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(change.name), change.value)...)
+	}
 }
 
 func (r *KeyValueResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data KeyValueResourceModel
+	var data, state KeyValueResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(provider.checkChangeWindow(data.RespectChangeWindow)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if !data.ExpiresAt.IsNull() {
+		if _, err := ttlUntil(data.ExpiresAt.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("expires_at"),
+				"Invalid etcd keyvalue expiry",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(data.validateSchema()...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	if !data.RequireQuorumMembers.IsNull() {
+		if err := provider.requireHealthyMembers(ctx, data.RequireQuorumMembers.ValueInt64()); err != nil {
+			resp.Diagnostics.AddError(
+				"etcd cluster degraded",
+				"Refusing to update while the cluster is degraded: "+err.Error(),
+			)
+			return
+		}
+	}
+
 	// Create new etcd client from config
-	client, err := clientv2.New(*r.cfg)
+	kApi, err := provider.keysAPI()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create etcdv2 API client",
@@ -169,22 +765,140 @@ func (r *KeyValueResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	// Retrieve KeyAPI from client
-	kApi := clientv2.NewKeysAPI(client)
+	if !data.DependsOnIndex.IsNull() {
+		if err := requireIndex(ctx, kApi, uint64(data.DependsOnIndex.ValueInt64())); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to satisfy etcd keyvalue depends_on_index",
+				errorDetail(err),
+			)
+			return
+		}
+	}
 
-	keyvalue, err := kApi.Set(context.Background(), data.Key.ValueString(), data.Value.ValueString(), nil)
-	if err != nil {
+	if data.ShadowHistory.ValueBool() {
+		retention := int64(defaultHistoryRetention)
+		if !data.HistoryRetention.IsNull() {
+			retention = data.HistoryRetention.ValueInt64()
+		}
+
+		if err := shadowHistory(ctx, kApi, state.Key.ValueString(), retention); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to copy etcd keyvalue to history",
+				errorDetail(err),
+			)
+			return
+		}
+	}
+
+	var node, prevNode, current *clientv2.Node
+
+	value := data.Value.ValueString()
+	if !data.DefaultValue.IsNull() {
+		// The default never overwrites the key, so it keeps its current value
+		keyvalue, err := kApi.Get(ctx, state.Key.ValueString(), nil)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read etcd keyvalue",
+				errorDetail(err),
+			)
+			return
+		}
+		current, value = keyvalue.Node, keyvalue.Node.Value
+	}
+
+	if !data.Key.Equal(state.Key) {
+		if data.RequireExistingParent.ValueBool() {
+			if err := requireParent(ctx, kApi, data.Key.ValueString()); err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to Rename etcd keyvalue",
+					errorDetail(err),
+				)
+				return
+			}
+		}
+
+		// A changed key is renamed in place rather than leaving the old key behind
+		node, prevNode, err = renameKey(ctx, kApi, state.Key.ValueString(), uint64(state.ModifiedIndex.ValueInt64()), data.Key.ValueString(), value)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Rename etcd keyvalue",
+				errorDetail(err),
+			)
+			return
+		}
+	} else if current != nil {
+		node = current
+	} else {
+		keyvalue, err := kApi.Set(context.Background(), data.Key.ValueString(), value, &clientv2.SetOptions{
+			PrevIndex: state.concurrentChangeGuard(),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Update etcd keyvalue",
+				errorDetail(err),
+			)
+			return
+		}
+		node, prevNode = keyvalue.Node, keyvalue.PrevNode
+	}
+
+	if !data.ExpiresAt.IsNull() {
+		node, err = applyExpiry(ctx, kApi, node, data.ExpiresAt.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to set etcd keyvalue expiry",
+				errorDetail(err),
+			)
+			return
+		}
+	}
+
+	if err := verifyWrite(ctx, kApi, node); err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Update etcd keyvalue",
-			err.Error(),
+			"Unable to verify etcd keyvalue update",
+			"The write was acknowledged but could not be read back from the cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
 		)
 		return
 	}
 
-	data.Value = types.StringValue(keyvalue.Node.Value)
-	data.ModifiedIndex = types.Int64Value(int64(keyvalue.Node.ModifiedIndex))
+	// Metadata follows a renamed key, and is removed once unset
+	if !state.Metadata.IsNull() && (data.Metadata.IsNull() || !data.Key.Equal(state.Key)) {
+		if err := deleteMetadata(ctx, kApi, state.Key.ValueString()); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Delete etcd keyvalue metadata",
+				errorDetail(err),
+			)
+			return
+		}
+	}
+	if !data.Metadata.IsNull() {
+		resp.Diagnostics.Append(data.storeMetadata(ctx, kApi)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if node != current {
+		resp.Diagnostics.Append(provider.recordChange(ctx, changeUpdate, node.Key, uint64(state.ModifiedIndex.ValueInt64()), node.ModifiedIndex)...)
+	}
+
+	if data.DefaultValue.IsNull() {
+		data.Value = types.StringValue(node.Value)
+	}
+	data.ModifiedIndex = data.trackedIndex(node.ModifiedIndex)
+	data.ValueSHA256, data.ValueMD5 = valueChecksums(types.StringValue(node.Value))
+	data.Adopted = types.BoolValue(false)
+	data.WasDefaulted = state.WasDefaulted
+	data.setPrevNode(prevNode)
+
+	if !data.RefreshInterval.IsNull() {
+		resp.Diagnostics.Append(markRead(ctx, resp.Private)...)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(data.checkExpectations(node)...)
 }
 
 func (r *KeyValueResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -197,8 +911,40 @@ func (r *KeyValueResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(provider.checkChangeWindow(data.RespectChangeWindow)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	if !data.RequireQuorumMembers.IsNull() {
+		if err := provider.requireHealthyMembers(ctx, data.RequireQuorumMembers.ValueInt64()); err != nil {
+			resp.Diagnostics.AddError(
+				"etcd cluster degraded",
+				"Refusing to delete while the cluster is degraded: "+err.Error(),
+			)
+			return
+		}
+	}
+
 	// Create new etcd client from config
-	client, err := clientv2.New(*r.cfg)
+	kApi, err := provider.keysAPI()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create etcdv2 API client",
@@ -208,18 +954,182 @@ func (r *KeyValueResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
-	// Retrieve KeyAPI from client
-	kApi := clientv2.NewKeysAPI(client)
-
-	_, err = kApi.Delete(context.Background(), data.Key.ValueString(), nil)
+	deleted, err := kApi.Delete(context.Background(), data.Key.ValueString(), &clientv2.DeleteOptions{
+		PrevIndex: data.concurrentChangeGuard(),
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error when trying to Delete etcd keyvalue",
-			err.Error(),
+			errorDetail(err),
 		)
 		return
 	}
 
+	resp.Diagnostics.Append(provider.recordChange(ctx, changeDelete, data.Key.ValueString(), prevIndex(deleted.PrevNode), deleted.Index)...)
+
+	if !data.Metadata.IsNull() {
+		if err := deleteMetadata(ctx, kApi, data.Key.ValueString()); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Delete etcd keyvalue metadata",
+				errorDetail(err),
+			)
+			return
+		}
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+func (r *KeyValueResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	fields, diags := r.provider.importFields(ctx, req.ID, "key", "index")
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key := fields["key"]
+
+	index, pinned, diags := parseImportIndex(fields)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if pinned {
+		resp.Diagnostics.Append(pinImportIndex(ctx, resp.Private, index)...)
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), key)...)
+
+	if cluster := r.provider.importCluster(req.ID); cluster != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster"), cluster)...)
+	}
+}
+
+// setPrevNode records the node replaced by a write, if there was one.
+func (m *KeyValueResourceModel) setPrevNode(prev *clientv2.Node) {
+	if prev == nil {
+		m.PrevValue = types.StringNull()
+		m.PrevModifiedIndex = types.Int64Null()
+		return
+	}
+
+	m.PrevValue = types.StringValue(prev.Value)
+	m.PrevModifiedIndex = types.Int64Value(int64(prev.ModifiedIndex))
+}
+
+// storeMetadata writes the configured metadata next to the key.
+func (m *KeyValueResourceModel) storeMetadata(ctx context.Context, kApi clientv2.KeysAPI) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var metadata map[string]string
+	diags.Append(m.Metadata.ElementsAs(ctx, &metadata, false)...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	if err := writeMetadata(ctx, kApi, m.Key.ValueString(), metadata); err != nil {
+		diags.AddError(
+			"Unable to Write etcd keyvalue metadata",
+			errorDetail(err),
+		)
+	}
+
+	return diags
+}
+
+// trackedIndex returns index as the modified_index to store, or null when
+// track_modified_index is disabled.
+func (m *KeyValueResourceModel) trackedIndex(index uint64) types.Int64 {
+	if !m.TrackModifiedIndex.IsNull() && !m.TrackModifiedIndex.ValueBool() {
+		return types.Int64Null()
+	}
+
+	return types.Int64Value(int64(index))
+}
+
+// validateSchema validates the configured value against validation's JSON
+// Schema.
+func (m *KeyValueResourceModel) validateSchema() diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if m.Validation == nil || m.Validation.JSONSchema.IsNull() {
+		return diags
+	}
+
+	jsonSchema, err := parseJSONSchema(m.Validation.JSONSchema.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("validation").AtName("json_schema"),
+			"Invalid etcd keyvalue validation",
+			err.Error(),
+		)
+		return diags
+	}
+
+	attribute, value := "value", m.Value
+	if !m.DefaultValue.IsNull() {
+		attribute, value = "default_value", m.DefaultValue
+	}
+
+	if errs := validateJSONDocument(jsonSchema, value.ValueString()); len(errs) > 0 {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Invalid etcd keyvalue value",
+			"The value does not match the validation JSON Schema:\n  - "+strings.Join(errs, "\n  - "),
+		)
+	}
+
+	return diags
+}
+
+// checkExpectations checks the written node against the verify block. State
+// is set before it is called, so the written key stays tracked if it fails.
+func (m *KeyValueResourceModel) checkExpectations(node *clientv2.Node) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if m.Verify == nil {
+		return diags
+	}
+
+	if pattern := m.Verify.ExpectValueRegex.ValueString(); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err == nil && !re.MatchString(node.Value) {
+			err = fmt.Errorf("the value %q does not match %s", node.Value, pattern)
+		}
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("verify").AtName("expect_value_regex"),
+				"etcd keyvalue verification failed",
+				"The key "+node.Key+" was written but "+err.Error(),
+			)
+		}
+	}
+
+	if !m.Verify.ExpectTTLMin.IsNull() {
+		minTTL := m.Verify.ExpectTTLMin.ValueInt64()
+		if node.Expiration == nil || node.TTL < minTTL {
+			diags.AddAttributeError(
+				path.Root("verify").AtName("expect_ttl_min"),
+				"etcd keyvalue verification failed",
+				fmt.Sprintf("The key %s was written with a TTL of %d seconds, expected at least %d", node.Key, node.TTL, minTTL),
+			)
+		}
+	}
+
+	return diags
+}
+
+// concurrentChangeGuard returns the index a write must be conditional on, or 0
+// when abort_on_concurrent_change is disabled.
+func (m *KeyValueResourceModel) concurrentChangeGuard() uint64 {
+	if !m.AbortOnConcurrentChange.ValueBool() {
+		return 0
+	}
+
+	return uint64(m.ModifiedIndex.ValueInt64())
+}
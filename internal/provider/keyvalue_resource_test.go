@@ -0,0 +1,384 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"terraform-provider-etcdv2/internal/testetcd"
+
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestAccKeyvalueResource(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: etcd.ProviderConfig() + testAccKeyvalueResourceConfig("one"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("etcdv2_keyvalue.test", "key", "/app/test"),
+					resource.TestCheckResourceAttr("etcdv2_keyvalue.test", "value", "one"),
+					testAccCheckEtcdValue(etcd, "/app/test", "one"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:                         "etcdv2_keyvalue.test",
+				ImportState:                          true,
+				ImportStateId:                        "/app/test",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "key",
+				// Both describe how the key was created, which an import
+				// cannot tell
+				ImportStateVerifyIgnore: []string{"adopted", "was_defaulted"},
+			},
+			// Update and Read testing
+			{
+				Config: etcd.ProviderConfig() + testAccKeyvalueResourceConfig("two"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("etcdv2_keyvalue.test", "value", "two"),
+					testAccCheckEtcdValue(etcd, "/app/test", "two"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+		CheckDestroy: testAccCheckEtcdMissing(etcd, "/app/test"),
+	})
+}
+
+func TestAccKeyvalueResource_auth(t *testing.T) {
+	etcd := testetcd.Start(t, testetcd.WithTLS(), testetcd.WithAuth("root", "secret"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: etcd.ProviderConfig() + testAccKeyvalueResourceConfig("one"),
+				Check:  testAccCheckEtcdValue(etcd, "/app/test", "one"),
+			},
+		},
+	})
+}
+
+// TestAccKeyvalueResource_roundTrip writes random keys and values through
+// Create, Read and Update, checking that etcd holds them unchanged and that a
+// refresh finds nothing to change.
+func TestAccKeyvalueResource_roundTrip(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	seed := time.Now().UnixNano()
+	t.Logf("seed %d", seed)
+	rnd := rand.New(rand.NewSource(seed))
+
+	keys := make([]string, 8)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("/roundtrip/%s/%d", randomKeySegment(rnd), i)
+	}
+
+	steps := make([]resource.TestStep, 2)
+	for i := range steps {
+		values := make(map[string]string, len(keys))
+		variables := make([]config.Variable, len(keys))
+		for j, key := range keys {
+			switch j {
+			case 0:
+				piece := randomValue(rnd)
+				values[key] = strings.Repeat(piece, 1<<20/len(piece)+1)
+			case 1:
+				values[key] = ""
+			default:
+				values[key] = randomValue(rnd)
+			}
+			variables[j] = config.StringVariable(values[key])
+		}
+
+		steps[i] = resource.TestStep{
+			Config: etcd.ProviderConfig() + testAccKeyvalueResourceRoundTripConfig,
+			ConfigVariables: config.Variables{
+				"keys":   stringListVariable(keys),
+				"values": config.ListVariable(variables...),
+			},
+			Check: testAccCheckEtcdValues(etcd, values),
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps:                    steps,
+		CheckDestroy:             testAccCheckEtcdMissing(etcd, keys...),
+	})
+}
+
+const testAccKeyvalueResourceRoundTripConfig = `
+variable "keys" {
+  type = list(string)
+}
+
+variable "values" {
+  type = list(string)
+}
+
+resource "etcdv2_keyvalue" "test" {
+  count = length(var.keys)
+
+  key   = var.keys[count.index]
+  value = var.values[count.index]
+}
+`
+
+// randomKeySegment returns a non-empty key segment of letters, digits and
+// punctuation from across unicode.
+func randomKeySegment(rnd *rand.Rand) string {
+	alphabet := []rune("abcXYZ019-_.~ äøß日本語ü€😀")
+
+	segment := make([]rune, 1+rnd.Intn(16))
+	for i := range segment {
+		segment[i] = alphabet[rnd.Intn(len(alphabet))]
+	}
+
+	// "." and ".." are not keys of their own
+	return "k" + string(segment)
+}
+
+// randomValue returns a random string of any runes, control characters
+// included. Terraform normalizes strings to NFC, so values are too.
+func randomValue(rnd *rand.Rand) string {
+	v, ok := quick.Value(reflect.TypeOf(""), rnd)
+	if !ok || v.String() == "" {
+		return "\x00\n\t\"quoted\" ${not_interpolated}"
+	}
+
+	return norm.NFC.String(v.String())
+}
+
+func stringListVariable(values []string) config.Variable {
+	variables := make([]config.Variable, len(values))
+	for i, v := range values {
+		variables[i] = config.StringVariable(v)
+	}
+
+	return config.ListVariable(variables...)
+}
+
+// testAccCheckEtcdValues checks that each key holds its value on the server.
+func testAccCheckEtcdValues(etcd *testetcd.Server, values map[string]string) resource.TestCheckFunc {
+	checks := make([]resource.TestCheckFunc, 0, len(values))
+	for key, value := range values {
+		checks = append(checks, testAccCheckEtcdValue(etcd, key, value))
+	}
+
+	return resource.ComposeAggregateTestCheckFunc(checks...)
+}
+
+func TestAccKeyvalueResource_concurrentChange(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: etcd.ProviderConfig() + testAccKeyvalueResourceCASConfig("one"),
+				Check:  testAccCheckEtcdValue(etcd, "/app/cas", "one"),
+			},
+			// A write made after the plan refreshed the key is caught by the
+			// compare-and-swap
+			{
+				PreConfig: func() {
+					etcd.BeforeNext("PUT", "/v2/keys/app/cas", func() { etcd.Set("/app/cas", "theirs") })
+				},
+				Config:      etcd.ProviderConfig() + testAccKeyvalueResourceCASConfig("two"),
+				ExpectError: regexp.MustCompile(`Compare failed`),
+			},
+			// Once refreshed, the update applies on top of the other write
+			{
+				PreConfig: func() {
+					if value, _ := etcd.Value("/app/cas"); value != "theirs" {
+						t.Errorf("the failed update overwrote the concurrent write with %q", value)
+					}
+				},
+				Config: etcd.ProviderConfig() + testAccKeyvalueResourceCASConfig("two"),
+				Check:  testAccCheckEtcdValue(etcd, "/app/cas", "two"),
+			},
+		},
+		CheckDestroy: testAccCheckEtcdMissing(etcd, "/app/cas"),
+	})
+}
+
+func TestAccKeyvalueResource_importIndex(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	index := etcd.Set("/app/imported", "existing")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        etcd.ProviderConfig() + testAccKeyvalueResourceImportConfig,
+				ResourceName:  "etcdv2_keyvalue.test",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("key=/app/imported,index=%d", index+1),
+				ExpectError:   regexp.MustCompile(`pinned to\s+index`),
+			},
+			{
+				Config:        etcd.ProviderConfig() + testAccKeyvalueResourceImportConfig,
+				ResourceName:  "etcdv2_keyvalue.test",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("key=/app/imported,index=%d", index),
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("imported %d resources, want 1", len(states))
+					}
+					if value := states[0].Attributes["value"]; value != "existing" {
+						return fmt.Errorf("imported value %q, want %q", value, "existing")
+					}
+
+					return nil
+				},
+			},
+			// An ID without a field name is the key, even with an "@" in it
+			{
+				PreConfig:     func() { etcd.Set("/app/imported@2", "at") },
+				Config:        etcd.ProviderConfig() + testAccKeyvalueResourceImportConfig,
+				ResourceName:  "etcdv2_keyvalue.test",
+				ImportState:   true,
+				ImportStateId: "/app/imported@2",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if key := states[0].Attributes["key"]; key != "/app/imported@2" {
+						return fmt.Errorf("imported key %q, want %q", key, "/app/imported@2")
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+const testAccKeyvalueResourceImportConfig = `
+resource "etcdv2_keyvalue" "test" {
+  key   = "/app/imported"
+  value = "existing"
+}
+`
+
+func testAccKeyvalueResourceCASConfig(value string) string {
+	return fmt.Sprintf(`
+resource "etcdv2_keyvalue" "test" {
+  key   = "/app/cas"
+  value = %[1]q
+
+  abort_on_concurrent_change = true
+}
+`, value)
+}
+
+func testAccKeyvalueResourceConfig(value string) string {
+	return fmt.Sprintf(`
+resource "etcdv2_keyvalue" "test" {
+  key   = "/app/test"
+  value = %[1]q
+}
+`, value)
+}
+
+// testAccCheckEtcdValue checks that key holds value on the server itself,
+// rather than as the provider last read it.
+func testAccCheckEtcdValue(etcd *testetcd.Server, key, value string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		got, ok := etcd.Value(key)
+		if !ok {
+			return fmt.Errorf("key %s does not exist", key)
+		}
+		if got != value {
+			return fmt.Errorf("key %s holds %q, want %q", key, got, value)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckEtcdMissing(etcd *testetcd.Server, keys ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		for _, key := range keys {
+			if _, ok := etcd.Value(key); ok {
+				return fmt.Errorf("key %s still exists", key)
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestAccKeyvalueResource_conflict(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: etcd.ProviderConfig() + `
+resource "etcdv2_keyvalue" "one" {
+  key   = "/app/shared"
+  value = "one"
+}
+
+resource "etcdv2_keyvalue" "two" {
+  key   = "/app/shared"
+  value = "two"
+}
+`,
+				ExpectError: regexp.MustCompile(`Conflicting etcd key`),
+			},
+			{
+				Config: etcd.ProviderConfig() + testAccKeyvalueResourceConfig("one"),
+				Check:  testAccCheckEtcdValue(etcd, "/app/test", "one"),
+			},
+			// A copy of the block is told apart from the resource it was
+			// copied from by the prior state
+			{
+				Config: etcd.ProviderConfig() + testAccKeyvalueResourceConfig("one") + `
+resource "etcdv2_keyvalue" "copy" {
+  key   = "/app/test"
+  value = "one"
+}
+`,
+				ExpectError: regexp.MustCompile(`Conflicting etcd key`),
+			},
+			// Replacing a resource claims its new key only once
+			{
+				Config: etcd.ProviderConfig() + `
+resource "etcdv2_keyvalue" "test" {
+  key   = "/app/moved"
+  value = "one"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckEtcdValue(etcd, "/app/moved", "one"),
+					testAccCheckEtcdMissing(etcd, "/app/test"),
+				),
+			},
+		},
+		CheckDestroy: testAccCheckEtcdMissing(etcd, "/app/shared", "/app/moved"),
+	})
+}
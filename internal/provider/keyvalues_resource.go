@@ -0,0 +1,638 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &KeyValuesResource{}
+	_ resource.ResourceWithConfigure   = &KeyValuesResource{}
+	_ resource.ResourceWithImportState = &KeyValuesResource{}
+	_ resource.ResourceWithModifyPlan  = &KeyValuesResource{}
+
+	_ resource.ResourceWithValidateConfig = &KeyValuesResource{}
+)
+
+func NewKeyValuesResource() resource.Resource {
+	return &KeyValuesResource{}
+}
+
+// KeyValuesResource defines the resource implementation
+type KeyValuesResource struct {
+	provider *etcdv2ProviderData
+}
+
+// KeyValuesResourceModel describes the resource data model.
+type KeyValuesResourceModel struct {
+	Prefix      types.String `tfsdk:"prefix"`
+	Data        types.Map    `tfsdk:"data"`
+	MaxChildren types.Int64  `tfsdk:"max_children"`
+	OnRemoval   types.String `tfsdk:"on_removal"`
+
+	RequireQuorumMembers types.Int64 `tfsdk:"require_quorum_members"`
+
+	Cluster types.String `tfsdk:"cluster"`
+
+	RespectChangeWindow types.Bool `tfsdk:"respect_change_window"`
+}
+
+func (r *KeyValuesResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_keyvalues"
+}
+
+func (r *KeyValuesResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a set of etcd keys beneath a common prefix. " +
+			"Importing by prefix adopts every existing key beneath it. Should creating fail part way, the keys already written are deleted again. " +
+			"Planning fails if another resource writes one of the keys, except for two new resources configured identically, which cannot be told apart.",
+		Attributes: map[string]schema.Attribute{
+			"cluster": schema.StringAttribute{
+				MarkdownDescription: "The name of a cluster in the provider's `endpoints_by_name` to manage the keys in instead of `host`. Changing it recreates the resource",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"respect_change_window": schema.BoolAttribute{
+				MarkdownDescription: "Fail to create, update or delete the keys outside of the provider's `change_window_cron`",
+				Optional:            true,
+			},
+			"prefix": schema.StringAttribute{
+				MarkdownDescription: "The directory the keys live under (e.g. '/app/config')",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"data": schema.MapAttribute{
+				MarkdownDescription: "The values to store, keyed by their path relative to `prefix` without a leading '/'",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+			"max_children": schema.Int64Attribute{
+				MarkdownDescription: "Fail the apply if `prefix` would hold more than this many direct children, including keys not managed here",
+				Optional:            true,
+			},
+			"on_removal": schema.StringAttribute{
+				MarkdownDescription: "What happens to keys removed from `data` or left behind when the resource is destroyed, " +
+					"either `delete` to delete them in etcd or `orphan` to only stop managing them. Defaults to `delete`",
+				Optional: true,
+			},
+			"require_quorum_members": schema.Int64Attribute{
+				MarkdownDescription: "Refuse updates and deletes unless at least this many cluster members report healthy, " +
+					"so the keys are never modified while the cluster is degraded",
+				Optional: true,
+			},
+		},
+	}
+}
+
+const (
+	onRemovalDelete = "delete"
+	onRemovalOrphan = "orphan"
+)
+
+func (r *KeyValuesResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data KeyValuesResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch data.OnRemoval.ValueString() {
+	case "", onRemovalDelete, onRemovalOrphan:
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("on_removal"),
+			"Invalid etcd keyvalues on_removal",
+			"on_removal must be one of "+onRemovalDelete+" or "+onRemovalOrphan+".",
+		)
+	}
+
+	if data.Data.IsUnknown() {
+		return
+	}
+
+	for name := range data.Data.Elements() {
+		if strings.HasPrefix(name, "/") {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("data").AtMapKey(name),
+				"Invalid etcd keyvalues name",
+				"Names are relative to the prefix and must not start with '/', use "+strings.TrimLeft(name, "/")+" instead.",
+			)
+		}
+	}
+}
+
+func (r *KeyValuesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(r.provider.checkPlannedKeys(ctx, req.Plan, "prefix")...)
+
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan KeyValuesResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(plan.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || plan.Prefix.IsUnknown() || plan.Data.IsUnknown() {
+		return
+	}
+
+	owner := claimOwner("etcdv2_keyvalues", "prefix "+plan.Prefix.ValueString(), req.Config, req.State)
+	for name := range plan.Data.Elements() {
+		key := childKey(plan.Prefix.ValueString(), name)
+		resp.Diagnostics.Append(provider.claimKey(path.Root("data").AtMapKey(name), key, owner)...)
+	}
+}
+
+func (r *KeyValuesResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	r.provider = provider
+}
+
+func (r *KeyValuesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data KeyValuesResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(provider.checkChangeWindow(data.RespectChangeWindow)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	kApi, err := provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	values := make(map[string]string)
+	resp.Diagnostics.Append(data.Data.ElementsAs(ctx, &values, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.MaxChildren.IsNull() {
+		err := checkMaxChildren(ctx, kApi, data.Prefix.ValueString(), data.MaxChildren.ValueInt64(), topLevelNames(values), nil)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_children"),
+				"Too many etcd directory entries",
+				errorDetail(err),
+			)
+			return
+		}
+	}
+
+	// Keys written so far, by name, with the index they were written at
+	written := make(map[string]uint64)
+
+	for _, name := range sortedNames(values) {
+		key := childKey(data.Prefix.ValueString(), name)
+
+		keyvalue, err := kApi.Create(ctx, key, values[name])
+		if err == nil {
+			written[name] = keyvalue.Node.ModifiedIndex
+			resp.Diagnostics.Append(provider.recordChange(ctx, changeCreate, key, 0, keyvalue.Node.ModifiedIndex)...)
+			err = verifyWrite(ctx, kApi, keyvalue.Node)
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("data").AtMapKey(name),
+				"Unable to Create etcd keyvalue",
+				errorDetail(err),
+			)
+
+			kept, diags := rollbackCreate(ctx, provider, kApi, data.Prefix.ValueString(), values, written)
+			resp.Diagnostics.Append(diags...)
+
+			// Otherwise keep what was created, so the next apply replaces it
+			if len(kept) > 0 {
+				partial, diags := types.MapValueFrom(ctx, types.StringType, kept)
+				resp.Diagnostics.Append(diags...)
+				data.Data = partial
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			}
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// rollbackCreate deletes the keys a failed Create already wrote, so a retry
+// does not fail on them, and returns those that could not be deleted. Keys
+// changed since they were written are left alone.
+func rollbackCreate(ctx context.Context, provider *etcdv2ProviderData, kApi clientv2.KeysAPI, prefix string, values map[string]string, written map[string]uint64) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	kept := make(map[string]string)
+	var errs []string
+
+	for _, name := range sortedNames(values) {
+		index, ok := written[name]
+		if !ok {
+			continue
+		}
+
+		key := childKey(prefix, name)
+		deleted, err := kApi.Delete(ctx, key, &clientv2.DeleteOptions{PrevIndex: index})
+		if err != nil && !clientv2.IsKeyNotFound(err) {
+			kept[name] = values[name]
+			errs = append(errs, key+": "+errorDetail(err))
+			continue
+		}
+		if err == nil {
+			diags.Append(provider.recordChange(ctx, changeDelete, key, prevIndex(deleted.PrevNode), deleted.Index)...)
+		}
+	}
+
+	if len(kept) > 0 {
+		diags.AddWarning(
+			"Unable to roll back partially created etcd keyvalues",
+			"The keys that could not be deleted were saved to state with the resource:\n\n"+strings.Join(errs, "\n"),
+		)
+	}
+
+	return kept, diags
+}
+
+func (r *KeyValuesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data KeyValuesResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new etcd client from config
+	kApi, err := provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	tree, err := kApi.Get(ctx, data.Prefix.ValueString(), &clientv2.GetOptions{Recursive: true})
+	if clientv2.IsKeyNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd keyvalues",
+			errorDetail(err),
+		)
+		return
+	}
+
+	remote := make(map[string]string)
+	flattenNodes(data.Prefix.ValueString(), tree.Node.Nodes, remote)
+
+	// A null map means the resource is being imported, so adopt every key.
+	// Otherwise only the keys already under management are refreshed.
+	if !data.Data.IsNull() {
+		managed := make(map[string]string)
+		resp.Diagnostics.Append(data.Data.ElementsAs(ctx, &managed, false)...)
+
+		for name := range managed {
+			// Names are relative to the prefix, as childKey writes them
+			if value, ok := remote[strings.TrimPrefix(name, "/")]; ok {
+				managed[name] = value
+			} else {
+				delete(managed, name)
+			}
+		}
+		remote = managed
+	}
+
+	values, diags := types.MapValueFrom(ctx, types.StringType, remote)
+	resp.Diagnostics.Append(diags...)
+	data.Data = values
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeyValuesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state KeyValuesResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(provider.checkChangeWindow(data.RespectChangeWindow)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	if !data.RequireQuorumMembers.IsNull() {
+		if err := provider.requireHealthyMembers(ctx, data.RequireQuorumMembers.ValueInt64()); err != nil {
+			resp.Diagnostics.AddError(
+				"etcd cluster degraded",
+				"Refusing to update while the cluster is degraded: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// Create new etcd client from config
+	kApi, err := provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	planned := make(map[string]string)
+	current := make(map[string]string)
+	resp.Diagnostics.Append(data.Data.ElementsAs(ctx, &planned, false)...)
+	resp.Diagnostics.Append(state.Data.ElementsAs(ctx, &current, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	orphan := data.OnRemoval.ValueString() == onRemovalOrphan
+
+	if !data.MaxChildren.IsNull() {
+		// Only removed leaves free up an entry, as a removed key in a
+		// subdirectory may leave other keys behind. Orphaned keys stay
+		removed := make(map[string]bool)
+		for name := range current {
+			if _, ok := planned[name]; !ok && !orphan && !strings.Contains(strings.TrimPrefix(name, "/"), "/") {
+				removed[strings.TrimPrefix(name, "/")] = true
+			}
+		}
+
+		err := checkMaxChildren(ctx, kApi, data.Prefix.ValueString(), data.MaxChildren.ValueInt64(), topLevelNames(planned), removed)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_children"),
+				"Too many etcd directory entries",
+				errorDetail(err),
+			)
+			return
+		}
+	}
+
+	for _, name := range sortedNames(current) {
+		if _, ok := planned[name]; ok || orphan {
+			continue
+		}
+
+		deleted, err := kApi.Delete(ctx, childKey(data.Prefix.ValueString(), name), nil)
+		if err != nil && !clientv2.IsKeyNotFound(err) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("data").AtMapKey(name),
+				"Error when trying to Delete etcd keyvalue",
+				errorDetail(err),
+			)
+			return
+		}
+		if err == nil {
+			resp.Diagnostics.Append(provider.recordChange(ctx, changeDelete, deleted.Node.Key, prevIndex(deleted.PrevNode), deleted.Index)...)
+		}
+	}
+
+	for _, name := range sortedNames(planned) {
+		if value, ok := current[name]; ok && value == planned[name] {
+			continue
+		}
+
+		keyvalue, err := kApi.Set(ctx, childKey(data.Prefix.ValueString(), name), planned[name], nil)
+		if err == nil {
+			err = verifyWrite(ctx, kApi, keyvalue.Node)
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("data").AtMapKey(name),
+				"Unable to Update etcd keyvalue",
+				errorDetail(err),
+			)
+			return
+		}
+
+		resp.Diagnostics.Append(provider.recordChange(ctx, changeUpdate, keyvalue.Node.Key, prevIndex(keyvalue.PrevNode), keyvalue.Node.ModifiedIndex)...)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeyValuesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data KeyValuesResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, diags := r.provider.forCluster(data.Cluster)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(provider.checkChangeWindow(data.RespectChangeWindow)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Orphaned keys are left in etcd and only removed from state
+	if data.OnRemoval.ValueString() == onRemovalOrphan {
+		return
+	}
+
+	if err := provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	if !data.RequireQuorumMembers.IsNull() {
+		if err := provider.requireHealthyMembers(ctx, data.RequireQuorumMembers.ValueInt64()); err != nil {
+			resp.Diagnostics.AddError(
+				"etcd cluster degraded",
+				"Refusing to delete while the cluster is degraded: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// Create new etcd client from config
+	kApi, err := provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	values := make(map[string]string)
+	resp.Diagnostics.Append(data.Data.ElementsAs(ctx, &values, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, name := range sortedNames(values) {
+		deleted, err := kApi.Delete(ctx, childKey(data.Prefix.ValueString(), name), nil)
+		if err != nil && !clientv2.IsKeyNotFound(err) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("data").AtMapKey(name),
+				"Error when trying to Delete etcd keyvalue",
+				errorDetail(err),
+			)
+			return
+		}
+		if err == nil {
+			resp.Diagnostics.Append(provider.recordChange(ctx, changeDelete, deleted.Node.Key, prevIndex(deleted.PrevNode), deleted.Index)...)
+		}
+	}
+}
+
+func (r *KeyValuesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, diags := r.provider.importID(ctx, req.ID, "prefix")
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("prefix"), id)...)
+
+	if cluster := r.provider.importCluster(req.ID); cluster != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster"), cluster)...)
+	}
+}
+
+// childKey joins a prefix and a relative name into a full etcd key.
+func childKey(prefix, name string) string {
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(name, "/")
+}
+
+// flattenNodes collects every non-directory node beneath prefix into values,
+// keyed by its path relative to prefix.
+func flattenNodes(prefix string, nodes clientv2.Nodes, values map[string]string) {
+	base := strings.TrimSuffix(prefix, "/") + "/"
+
+	for _, n := range nodes {
+		if n.Dir {
+			flattenNodes(prefix, n.Nodes, values)
+			continue
+		}
+		values[strings.TrimPrefix(n.Key, base)] = n.Value
+	}
+}
+
+// sortedNames returns the keys of values in a stable order so that writes are
+// issued deterministically.
+func sortedNames(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"terraform-provider-etcdv2/internal/testetcd"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccKeyvaluesResource(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: etcd.ProviderConfig() + testAccKeyvaluesResourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("etcdv2_keyvalues.test", "data.%", "3"),
+					testAccCheckEtcdValue(etcd, "/app/kvs/a", "1"),
+					testAccCheckEtcdValue(etcd, "/app/kvs/b", "2"),
+					testAccCheckEtcdValue(etcd, "/app/kvs/c", "3"),
+				),
+			},
+		},
+		CheckDestroy: testAccCheckEtcdMissing(etcd, "/app/kvs/a", "/app/kvs/b", "/app/kvs/c"),
+	})
+}
+
+// TestAccKeyvaluesResource_partialCreate fails a create after some keys were
+// written, which are rolled back so the retry can create them again.
+func TestAccKeyvaluesResource_partialCreate(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					etcd.InjectKeyError("PUT", "/app/kvs/c", clientv2.ErrorCodeRaftInternal, "Raft Internal Error")
+				},
+				Config:      etcd.ProviderConfig() + testAccKeyvaluesResourceConfig,
+				ExpectError: regexp.MustCompile("Unable to Create etcd keyvalue"),
+			},
+			{
+				PreConfig: func() {
+					if err := testAccCheckEtcdMissing(etcd, "/app/kvs/a", "/app/kvs/b")(nil); err != nil {
+						t.Errorf("the failed create was not rolled back: %s", err)
+					}
+				},
+				Config: etcd.ProviderConfig() + testAccKeyvaluesResourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckEtcdValue(etcd, "/app/kvs/a", "1"),
+					testAccCheckEtcdValue(etcd, "/app/kvs/b", "2"),
+					testAccCheckEtcdValue(etcd, "/app/kvs/c", "3"),
+				),
+			},
+		},
+		CheckDestroy: testAccCheckEtcdMissing(etcd, "/app/kvs/a", "/app/kvs/b", "/app/kvs/c"),
+	})
+}
+
+// TestAccKeyvaluesResource_partialRollback fails a create and the rollback of
+// one of the keys written, which is saved to state so the next apply replaces
+// it rather than failing on it.
+func TestAccKeyvaluesResource_partialRollback(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					etcd.InjectKeyError("PUT", "/app/kvs/c", clientv2.ErrorCodeRaftInternal, "Raft Internal Error")
+					etcd.InjectKeyError("DELETE", "/app/kvs/a", clientv2.ErrorCodeRaftInternal, "Raft Internal Error")
+				},
+				Config:      etcd.ProviderConfig() + testAccKeyvaluesResourceConfig,
+				ExpectError: regexp.MustCompile("Unable to Create etcd keyvalue"),
+			},
+			{
+				PreConfig: func() {
+					if err := testAccCheckEtcdValue(etcd, "/app/kvs/a", "1")(nil); err != nil {
+						t.Errorf("the key whose rollback failed is gone: %s", err)
+					}
+					if err := testAccCheckEtcdMissing(etcd, "/app/kvs/b")(nil); err != nil {
+						t.Errorf("the failed create was not rolled back: %s", err)
+					}
+				},
+				Config: etcd.ProviderConfig() + testAccKeyvaluesResourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckEtcdValue(etcd, "/app/kvs/a", "1"),
+					testAccCheckEtcdValue(etcd, "/app/kvs/b", "2"),
+					testAccCheckEtcdValue(etcd, "/app/kvs/c", "3"),
+				),
+			},
+		},
+		CheckDestroy: testAccCheckEtcdMissing(etcd, "/app/kvs/a", "/app/kvs/b", "/app/kvs/c"),
+	})
+}
+
+const testAccKeyvaluesResourceConfig = `
+resource "etcdv2_keyvalues" "test" {
+  prefix = "/app/kvs"
+
+  data = {
+    a = "1"
+    b = "2"
+    c = "3"
+  }
+}
+`
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// lastWriterStampName is the hidden key holding the stamp beneath the mirrored
+// path of every key.
+const lastWriterStampName = "_last_writer"
+
+// lastWriter stamps every key the provider writes with who wrote it last, in
+// a parallel key beneath a separate directory so the stamped values and
+// directory listings are left untouched.
+type lastWriter struct {
+	dir       string
+	workspace string
+	runID     string
+}
+
+// lastWriterStamp is the JSON stored for each written key.
+type lastWriterStamp struct {
+	Operation string    `json:"operation"`
+	Index     uint64    `json:"index,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Workspace string    `json:"workspace"`
+	RunID     string    `json:"run_id,omitempty"`
+}
+
+// newLastWriter stamps keys beneath dir. The run ID is taken from TFC_RUN_ID,
+// which HCP Terraform and Terraform Enterprise set for every run.
+func newLastWriter(dir, workspace string) *lastWriter {
+	return &lastWriter{
+		dir:       strings.TrimSuffix(dir, "/"),
+		workspace: changeWorkspace(workspace),
+		runID:     os.Getenv("TFC_RUN_ID"),
+	}
+}
+
+// stampLastWriter writes the stamp of key when last_writer_key is set. The
+// write has already happened, so a failure to stamp it is a warning.
+func (p *etcdv2ProviderData) stampLastWriter(ctx context.Context, operation, key string, index uint64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if p.lastWriter == nil {
+		return diags
+	}
+
+	// The stamp is a child of the mirrored key, so the stamps of a directory
+	// and of the keys beneath it do not collide
+	stamp := p.lastWriter.dir + "/" + strings.Trim(key, "/") + "/" + lastWriterStampName
+
+	if err := p.lastWriter.stamp(ctx, p, stamp, lastWriterStamp{
+		Operation: operation,
+		Index:     index,
+		Timestamp: time.Now().UTC(),
+		Workspace: p.lastWriter.workspace,
+		RunID:     p.lastWriter.runID,
+	}); err != nil {
+		diags.AddWarning(
+			"Unable to stamp last writer of etcd key",
+			"The "+operation+" of "+key+" was applied but "+stamp+" could not be written.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+	}
+
+	return diags
+}
+
+func (l *lastWriter) stamp(ctx context.Context, p *etcdv2ProviderData, key string, stamp lastWriterStamp) error {
+	value, err := json.Marshal(stamp)
+	if err != nil {
+		return err
+	}
+
+	kApi, err := p.keysAPI()
+	if err != nil {
+		return err
+	}
+
+	_, err = kApi.Set(ctx, key, string(value), nil)
+	return err
+}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// leadershipPollInterval is the pause between leader checks while waiting.
+const leadershipPollInterval = time.Second
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource              = &LeadershipHintResource{}
+	_ resource.ResourceWithConfigure = &LeadershipHintResource{}
+)
+
+func NewLeadershipHintResource() resource.Resource {
+	return &LeadershipHintResource{}
+}
+
+// LeadershipHintResource defines the resource implementation
+type LeadershipHintResource struct {
+	provider *etcdv2ProviderData
+}
+
+// LeadershipHintResourceModel describes the resource data model.
+type LeadershipHintResourceModel struct {
+	Member      types.String `tfsdk:"member"`
+	WaitTimeout types.Int64  `tfsdk:"wait_timeout"`
+	LeaderID    types.String `tfsdk:"leader_id"`
+	LeaderName  types.String `tfsdk:"leader_name"`
+}
+
+func (r *LeadershipHintResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_leadership_hint"
+}
+
+func (r *LeadershipHintResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Checks that a member is the cluster leader before the apply continues, for maintenance playbooks. " +
+			"The v2 API cannot transfer leadership, so this only waits for and validates the leader. Destroying it does nothing.",
+		Attributes: map[string]schema.Attribute{
+			"member": schema.StringAttribute{
+				MarkdownDescription: "The name or ID of the member expected to lead",
+				Required:            true,
+			},
+			"wait_timeout": schema.Int64Attribute{
+				MarkdownDescription: "How long in seconds to wait for the member to become leader before failing. Defaults to 0, checking once",
+				Optional:            true,
+			},
+			"leader_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the leader at apply time",
+				Computed:            true,
+			},
+			"leader_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the leader at apply time",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *LeadershipHintResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	r.provider = provider
+}
+
+func (r *LeadershipHintResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data LeadershipHintResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.check(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *LeadershipHintResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The check only applies at apply time, so state is kept as is
+}
+
+func (r *LeadershipHintResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data LeadershipHintResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.check(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *LeadershipHintResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Nothing to release
+}
+
+// check waits up to wait_timeout for the configured member to lead.
+func (r *LeadershipHintResource) check(ctx context.Context, data *LeadershipHintResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Create new etcd client from config
+	client, err := clientv2.New(r.provider.clientConfig())
+	if err != nil {
+		diags.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return diags
+	}
+
+	mApi := clientv2.NewMembersAPI(client)
+
+	deadline := time.Now().Add(time.Duration(data.WaitTimeout.ValueInt64()) * time.Second)
+	member := data.Member.ValueString()
+
+	for {
+		leader, err := mApi.Leader(ctx)
+		if err != nil {
+			diags.AddError(
+				"Unable to Read etcd leader",
+				errorDetail(err),
+			)
+			return diags
+		}
+
+		if leader.ID == member || leader.Name == member {
+			data.LeaderID = types.StringValue(leader.ID)
+			data.LeaderName = types.StringValue(leader.Name)
+			return diags
+		}
+
+		if time.Now().Add(leadershipPollInterval).After(deadline) {
+			diags.AddAttributeError(
+				path.Root("member"),
+				"etcd member is not the leader",
+				fmt.Sprintf("The leader is %s (%s), not %s.", leader.Name, leader.ID, member),
+			)
+			return diags
+		}
+
+		select {
+		case <-ctx.Done():
+			diags.AddError("Unable to Read etcd leader", ctx.Err().Error())
+			return diags
+		case <-time.After(leadershipPollInterval):
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"sync"
+)
+
+// namedLocks hands out a mutex per name so that auth mutations touching the
+// same role or user serialize, while unrelated objects proceed in parallel.
+// Terraform runs resource operations concurrently, and the auth API has no
+// compare-and-swap to protect interleaved revoke and grant calls.
+type namedLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func newNamedLocks() *namedLocks {
+	return &namedLocks{
+		locks: make(map[string]*sync.Mutex),
+	}
+}
+
+// get returns the mutex for name, creating it on first use.
+func (l *namedLocks) get(name string) *sync.Mutex {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock, ok := l.locks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		l.locks[name] = lock
+	}
+
+	return lock
+}
+
+// lockRole locks the named role and returns the matching unlock function.
+func (p *etcdv2ProviderData) lockRole(name string) func() {
+	lock := p.authLocks.get("role/" + name)
+	lock.Lock()
+
+	return lock.Unlock
+}
+
+// lockUser locks the named user and returns the matching unlock function.
+func (p *etcdv2ProviderData) lockUser(name string) func() {
+	lock := p.authLocks.get("user/" + name)
+	lock.Lock()
+
+	return lock.Unlock
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+const (
+	// metadataManagedBy is the metadata field marking who manages a key.
+	metadataManagedBy = "managed-by"
+
+	// managedByTerraform is the managed-by value written for keys whose
+	// metadata this provider stores.
+	managedByTerraform = "terraform"
+
+	// metadataDir is the hidden sibling directory holding key metadata.
+	metadataDir = "_meta"
+)
+
+// metadataKey returns where the metadata of key is stored, the hidden sibling
+// <dir>/_meta/<name>. A leaf key cannot hold children in etcd v2, so the
+// metadata sits next to it.
+func metadataKey(key string) string {
+	return path.Join(path.Dir(key), metadataDir, path.Base(key))
+}
+
+// writeMetadata stores metadata for key as a JSON document, marking the key
+// as managed by Terraform unless metadata sets managed-by itself.
+func writeMetadata(ctx context.Context, kApi clientv2.KeysAPI, key string, metadata map[string]string) error {
+	doc := map[string]string{metadataManagedBy: managedByTerraform}
+	for k, v := range metadata {
+		doc[k] = v
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = kApi.Set(ctx, metadataKey(key), string(raw), nil)
+	return err
+}
+
+// readMetadata returns the metadata stored for key, or nil if there is none.
+// The managed-by marker added by writeMetadata is left out unless keepMarker
+// is set, so it does not show up as drift.
+func readMetadata(ctx context.Context, kApi clientv2.KeysAPI, key string, keepMarker bool) (map[string]string, error) {
+	resp, err := kApi.Get(ctx, metadataKey(key), nil)
+	if clientv2.IsKeyNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]string
+	if err := json.Unmarshal([]byte(resp.Node.Value), &doc); err != nil {
+		return nil, err
+	}
+
+	if !keepMarker && doc[metadataManagedBy] == managedByTerraform {
+		delete(doc, metadataManagedBy)
+	}
+
+	return doc, nil
+}
+
+// deleteMetadata removes the metadata stored for key, if there is any.
+func deleteMetadata(ctx context.Context, kApi clientv2.KeysAPI, key string) error {
+	_, err := kApi.Delete(ctx, metadataKey(key), nil)
+	if clientv2.IsKeyNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// managedKeys returns the keys directly beneath dir whose metadata has
+// managed-by set to marker.
+func managedKeys(ctx context.Context, kApi clientv2.KeysAPI, dir, marker string) (map[string]bool, error) {
+	resp, err := kApi.Get(ctx, path.Join(dir, metadataDir), nil)
+	if clientv2.IsKeyNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	managed := make(map[string]bool)
+	for _, n := range resp.Node.Nodes {
+		var doc map[string]string
+		if n.Dir || json.Unmarshal([]byte(n.Value), &doc) != nil {
+			continue
+		}
+
+		if doc[metadataManagedBy] == marker {
+			managed[path.Join(dir, path.Base(n.Key))] = true
+		}
+	}
+
+	return managed, nil
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// missingKeys caches keys that data sources found not to exist, so that a
+// key referenced by several data sources is only queried once per operation
+// and the errors of data sources can count the keys missing so far.
+type missingKeys struct {
+	mu   sync.Mutex
+	keys map[string]error
+}
+
+// lookup returns the not found error cached for key, or nil if the key is not
+// known to be missing.
+func (m *missingKeys) lookup(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.keys[key]
+}
+
+// record caches err for key if it reports the key as not found, and returns
+// whether it did.
+func (m *missingKeys) record(key string, err error) bool {
+	if !clientv2.IsKeyNotFound(err) {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.keys == nil {
+		m.keys = make(map[string]error)
+	}
+
+	m.keys[key] = err
+	return true
+}
+
+// Diagnostic returns the error for a data source reading key, which must
+// have been recorded as missing. It names only key, so each missing key is
+// listed once across the errors of a run, and counts the keys missing so far.
+func (m *missingKeys) Diagnostic(key string) diag.Diagnostic {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	detail := fmt.Sprintf("The key %s read by this data source does not exist.", key)
+	if n := len(m.keys); n > 1 {
+		detail += fmt.Sprintf("\n\n%d keys read by data sources are missing so far, each reported by the data sources reading it.", n)
+	}
+
+	return diag.NewErrorDiagnostic("etcd key missing", detail)
+}
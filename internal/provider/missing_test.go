@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+func TestMissingKeys(t *testing.T) {
+	var m missingKeys
+
+	keys := []string{"/app/a", "/app/b", "/app/c"}
+	for _, key := range keys {
+		if !m.record(key, clientv2.Error{Code: clientv2.ErrorCodeKeyNotFound, Cause: key}) {
+			t.Fatalf("%s was not recorded as missing", key)
+		}
+	}
+	if m.record("/app/d", clientv2.Error{Code: clientv2.ErrorCodeRaftInternal}) {
+		t.Error("an error other than not found was recorded as missing")
+	}
+
+	for _, key := range keys {
+		if m.lookup(key) == nil {
+			t.Errorf("%s is not cached as missing", key)
+		}
+
+		// Each error names its own key only, so no key is listed twice
+		detail := m.Diagnostic(key).Detail()
+		for _, other := range keys {
+			if got := strings.Count(detail, other); (other == key) != (got == 1) {
+				t.Errorf("the error for %s names %s %d times:\n%s", key, other, got, detail)
+			}
+		}
+		if !strings.Contains(detail, "3 keys read by data sources are missing") {
+			t.Errorf("the error for %s does not count the missing keys:\n%s", key, detail)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package provider
+
+import (
+	"context"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"terraform-provider-etcdv2/pkg/etcdv2ops"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource               = &PermissionBoundaryResource{}
+	_ resource.ResourceWithConfigure  = &PermissionBoundaryResource{}
+	_ resource.ResourceWithModifyPlan = &PermissionBoundaryResource{}
+)
+
+var rolePermissionAttrTypes = map[string]attr.Type{
+	"key":    types.StringType,
+	"access": types.StringType,
+}
+
+func NewPermissionBoundaryResource() resource.Resource {
+	return &PermissionBoundaryResource{}
+}
+
+// PermissionBoundaryResource defines the resource implementation
+type PermissionBoundaryResource struct {
+	provider *etcdv2ProviderData
+}
+
+// PermissionBoundaryResourceModel describes the resource data model.
+type PermissionBoundaryResourceModel struct {
+	Name                 types.String          `tfsdk:"name"`
+	Permissions          []RolePermissionModel `tfsdk:"permissions"`
+	EffectivePermissions types.Set             `tfsdk:"effective_permissions"`
+}
+
+func (r *PermissionBoundaryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_permission_boundary"
+}
+
+func (r *PermissionBoundaryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A role granted the requested permissions narrowed to the provider's `allowed_permission_prefixes`, " +
+			"so delegated teams can request access without being able to exceed it.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the role",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"permissions": schema.SetNestedAttribute{
+				MarkdownDescription: "The key permissions requested for the role",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							MarkdownDescription: "The key or key pattern, where a trailing '*' matches by prefix (e.g. '/foo/*')",
+							Required:            true,
+						},
+						"access": schema.StringAttribute{
+							MarkdownDescription: "One of `read`, `write` or `readwrite`",
+							Required:            true,
+						},
+					},
+				},
+			},
+			"effective_permissions": schema.SetNestedAttribute{
+				MarkdownDescription: "The permissions granted to the role, i.e. the requested permissions within the allowed prefixes",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							MarkdownDescription: "The key or key pattern",
+							Computed:            true,
+						},
+						"access": schema.StringAttribute{
+							MarkdownDescription: "One of `read`, `write` or `readwrite`",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *PermissionBoundaryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to compute on destroy
+	if req.Plan.Raw.IsNull() || r.provider == nil {
+		return
+	}
+
+	var plan PermissionBoundaryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, perm := range plan.Permissions {
+		if perm.Key.IsUnknown() || perm.Access.IsUnknown() {
+			return
+		}
+	}
+
+	effective, diags := permissionSet(ctx, plan.effective(r.provider.permissionPolicy))
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_permissions"), effective)...)
+}
+
+func (r *PermissionBoundaryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	r.provider = provider
+}
+
+func (r *PermissionBoundaryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PermissionBoundaryResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	client, err := clientv2.New(r.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Retrieve RoleAPI from client
+	rApi := clientv2.NewAuthRoleAPI(client)
+
+	defer r.provider.lockRole(data.Name.ValueString())()
+
+	if err := rApi.AddRole(ctx, data.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcd permission boundary role",
+			errorDetail(err),
+		)
+		return
+	}
+
+	perms := data.effective(r.provider.permissionPolicy)
+
+	if _, err := etcdv2ops.GrantRole(ctx, rApi, data.Name.ValueString(), perms); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Grant etcd permission boundary permissions",
+			errorDetail(err),
+		)
+
+		// Roll back so a retry does not fail with the role already existing
+		if rollbackErr := rApi.RemoveRole(ctx, data.Name.ValueString()); rollbackErr != nil {
+			resp.Diagnostics.AddWarning(
+				"Unable to roll back partially created etcd role",
+				"The role "+data.Name.ValueString()+" must be removed before retrying.\n\n"+
+					"etcdv2 Error: "+errorDetail(rollbackErr),
+			)
+		}
+		return
+	}
+
+	effective, diags := permissionSet(ctx, perms)
+	resp.Diagnostics.Append(diags...)
+	data.EffectivePermissions = effective
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PermissionBoundaryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PermissionBoundaryResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new etcd client from config, reading from the leader so a
+	// grant made just before is not missing from the permission list
+	client, err := r.provider.leaderClient(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	role, err := clientv2.NewAuthRoleAPI(client).GetRole(ctx, data.Name.ValueString())
+	if clientv2.IsRoleNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd permission boundary role",
+			errorDetail(err),
+		)
+		return
+	}
+
+	effective, diags := permissionSet(ctx, role.Permissions)
+	resp.Diagnostics.Append(diags...)
+	data.EffectivePermissions = effective
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PermissionBoundaryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state PermissionBoundaryResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var have []RolePermissionModel
+	resp.Diagnostics.Append(state.EffectivePermissions.ElementsAs(ctx, &have, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	client, err := clientv2.New(r.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Retrieve RoleAPI from client
+	rApi := clientv2.NewAuthRoleAPI(client)
+
+	defer r.provider.lockRole(data.Name.ValueString())()
+
+	perms := data.effective(r.provider.permissionPolicy)
+	current := RoleResourceModel{Permissions: have}
+
+	if err := etcdv2ops.SyncRole(ctx, rApi, data.Name.ValueString(), perms, current.permissions()); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Update etcd permission boundary permissions",
+			errorDetail(err),
+		)
+		return
+	}
+
+	effective, diags := permissionSet(ctx, perms)
+	resp.Diagnostics.Append(diags...)
+	data.EffectivePermissions = effective
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PermissionBoundaryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PermissionBoundaryResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	client, err := clientv2.New(r.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	defer r.provider.lockRole(data.Name.ValueString())()
+
+	err = clientv2.NewAuthRoleAPI(client).RemoveRole(ctx, data.Name.ValueString())
+	if err != nil && !clientv2.IsRoleNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error when trying to Delete etcd permission boundary role",
+			errorDetail(err),
+		)
+		return
+	}
+}
+
+// effective returns the requested permissions narrowed to what policy allows.
+func (m *PermissionBoundaryResourceModel) effective(policy *keyPolicy) clientv2.Permissions {
+	requested := RoleResourceModel{Permissions: m.Permissions}
+
+	var perms clientv2.Permissions
+	for _, key := range requested.permissions().KV.Read {
+		perms.KV.Read = append(perms.KV.Read, policy.intersect(key)...)
+	}
+	for _, key := range requested.permissions().KV.Write {
+		perms.KV.Write = append(perms.KV.Write, policy.intersect(key)...)
+	}
+
+	return perms
+}
+
+// permissionSet converts perms to the value of a permissions set attribute.
+func permissionSet(ctx context.Context, perms clientv2.Permissions) (types.Set, diag.Diagnostics) {
+	role := RoleResourceModel{Permissions: []RolePermissionModel{}}
+	role.setPermissions(perms)
+
+	return types.SetValueFrom(ctx, types.ObjectType{AttrTypes: rolePermissionAttrTypes}, role.Permissions)
+}
@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"context"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                   = &PrefixCleanupResource{}
+	_ resource.ResourceWithConfigure      = &PrefixCleanupResource{}
+	_ resource.ResourceWithModifyPlan     = &PrefixCleanupResource{}
+	_ resource.ResourceWithValidateConfig = &PrefixCleanupResource{}
+)
+
+func NewPrefixCleanupResource() resource.Resource {
+	return &PrefixCleanupResource{}
+}
+
+// PrefixCleanupResource defines the resource implementation
+type PrefixCleanupResource struct {
+	provider *etcdv2ProviderData
+}
+
+// PrefixCleanupResourceModel describes the resource data model.
+type PrefixCleanupResourceModel struct {
+	Prefix       types.String `tfsdk:"prefix"`
+	PlanPreview  types.Bool   `tfsdk:"plan_preview"`
+	KeysToRemove types.List   `tfsdk:"keys_to_remove"`
+
+	DeleteBatchSize types.Int64 `tfsdk:"delete_batch_size"`
+}
+
+func (r *PrefixCleanupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_prefix_cleanup"
+}
+
+func (r *PrefixCleanupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Recursively deletes everything beneath a prefix when destroyed, so tearing down an application namespace is explicit. " +
+			"Creating it writes nothing. The nodes are deleted one by one, children before their directory, so large prefixes do not " +
+			"time out as a single recursive delete would, and deletes failing without an answer from etcd are retried with back-off.",
+		Attributes: map[string]schema.Attribute{
+			"prefix": schema.StringAttribute{
+				MarkdownDescription: "The directory to delete on destroy (e.g. '/app')",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"plan_preview": schema.BoolAttribute{
+				MarkdownDescription: "List the keys a destroy would remove in `keys_to_remove` on every refresh",
+				Optional:            true,
+			},
+			"delete_batch_size": schema.Int64Attribute{
+				MarkdownDescription: "How many nodes are deleted between progress messages in the provider log on destroy. Defaults to 500",
+				Optional:            true,
+			},
+			"keys_to_remove": schema.ListAttribute{
+				MarkdownDescription: "The keys beneath `prefix` that a destroy would remove, when `plan_preview` is enabled",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *PrefixCleanupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data PrefixCleanupResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.DeleteBatchSize.IsUnknown() && !data.DeleteBatchSize.IsNull() && data.DeleteBatchSize.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("delete_batch_size"),
+			"Invalid delete batch size",
+			"The delete_batch_size must be at least 1.",
+		)
+	}
+}
+
+func (r *PrefixCleanupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(r.provider.checkPlannedKeys(ctx, req.Plan, "prefix")...)
+}
+
+func (r *PrefixCleanupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	r.provider = provider
+}
+
+func (r *PrefixCleanupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PrefixCleanupResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.preview(ctx, &data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PrefixCleanupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PrefixCleanupResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.preview(ctx, &data)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PrefixCleanupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data PrefixCleanupResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.preview(ctx, &data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PrefixCleanupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PrefixCleanupResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	kApi, err := r.provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	batchSize := defaultDeleteBatchSize
+	if !data.DeleteBatchSize.IsNull() {
+		batchSize = int(data.DeleteBatchSize.ValueInt64())
+	}
+
+	if err := deletePrefix(ctx, kApi, data.Prefix.ValueString(), batchSize); err != nil {
+		resp.Diagnostics.AddError(
+			"Error when trying to Delete etcd prefix",
+			errorDetail(err),
+		)
+		return
+	}
+}
+
+// preview fills keys_to_remove with the keys beneath the prefix, or null when
+// plan_preview is disabled.
+func (r *PrefixCleanupResource) preview(ctx context.Context, data *PrefixCleanupResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !data.PlanPreview.ValueBool() {
+		data.KeysToRemove = types.ListNull(types.StringType)
+		return diags
+	}
+
+	// Create new etcd client from config
+	kApi, err := r.provider.keysAPI()
+	if err != nil {
+		diags.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return diags
+	}
+
+	keys := []string{}
+
+	tree, err := kApi.Get(ctx, data.Prefix.ValueString(), &clientv2.GetOptions{Recursive: true, Sort: true})
+	if err != nil && !clientv2.IsKeyNotFound(err) {
+		diags.AddError(
+			"Unable to Read etcd prefix",
+			errorDetail(err),
+		)
+		return diags
+	}
+	if err == nil {
+		var walk func(clientv2.Nodes)
+		walk = func(children clientv2.Nodes) {
+			for _, n := range children {
+				if !n.Dir {
+					keys = append(keys, n.Key)
+				}
+				walk(n.Nodes)
+			}
+		}
+		walk(tree.Node.Nodes)
+	}
+
+	list, d := types.ListValueFrom(ctx, types.StringType, keys)
+	diags.Append(d...)
+	data.KeysToRemove = list
+
+	return diags
+}
@@ -5,6 +5,9 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
 	"os"
 	"time"
 
@@ -16,6 +19,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure implementation satisfies various provider interfaces.
@@ -38,11 +42,124 @@ type etcdv2Provider struct {
 }
 
 type etcdv2ProviderModel struct {
-	Host     types.String `tfsdk:"host"`
+	Host                types.String      `tfsdk:"host"`
+	Endpoints           types.List        `tfsdk:"endpoints"`
+	Username            types.String      `tfsdk:"username"`
+	Password            types.String      `tfsdk:"password"`
+	ReportDriftSummary  types.Bool        `tfsdk:"report_drift_summary"`
+	CheckWritePerms     types.Bool        `tfsdk:"check_write_permissions"`
+	FencingWorkspace    types.String      `tfsdk:"fencing_workspace"`
+	FencingTTL          types.Int64       `tfsdk:"fencing_ttl"`
+	FollowRedirects     types.Bool        `tfsdk:"follow_redirects"`
+	BearerToken         types.String      `tfsdk:"bearer_token"`
+	AuthHeader          types.String      `tfsdk:"auth_header"`
+	PathPrefix          types.String      `tfsdk:"path_prefix"`
+	CredentialsFromKey  types.String      `tfsdk:"credentials_from_key"`
+	LeaderElectionGrace types.Int64       `tfsdk:"leader_election_grace"`
+	CredentialsFile     types.String      `tfsdk:"credentials_file"`
+	CredentialsExec     types.List        `tfsdk:"credentials_exec"`
+	CACertDir           types.String      `tfsdk:"ca_cert_dir"`
+	CACertPEM           types.String      `tfsdk:"ca_cert_pem"`
+	ClientCertPEM       types.String      `tfsdk:"client_cert_pem"`
+	ClientKeyPEM        types.String      `tfsdk:"client_key_pem"`
+	CACertFile          types.String      `tfsdk:"ca_cert_file"`
+	ClientCertFile      types.String      `tfsdk:"client_cert_file"`
+	ClientKeyFile       types.String      `tfsdk:"client_key_file"`
+	Socks5Proxy         *socks5ProxyModel `tfsdk:"socks5_proxy"`
+	HTTPProxy           types.String      `tfsdk:"http_proxy"`
+	HTTPSProxy          types.String      `tfsdk:"https_proxy"`
+	NoProxy             types.String      `tfsdk:"no_proxy"`
+	SnapshotTolerance   types.Int64       `tfsdk:"snapshot_index_tolerance"`
+	ClusterAlias        types.String      `tfsdk:"cluster_alias"`
+	ChangeLogKey        types.String      `tfsdk:"change_log_key"`
+	LastWriterKey       types.String      `tfsdk:"last_writer_key"`
+	AllowedKeyPrefixes  types.List        `tfsdk:"allowed_key_prefixes"`
+	DeniedKeyPrefixes   types.List        `tfsdk:"denied_key_prefixes"`
+	SensitivePrefixes   types.List        `tfsdk:"sensitive_key_prefixes"`
+	AllowedPermPrefixes types.List        `tfsdk:"allowed_permission_prefixes"`
+	EndpointsByName     types.Map         `tfsdk:"endpoints_by_name"`
+	Telemetry           *telemetryModel   `tfsdk:"telemetry"`
+	ChangeWindowCron    types.String      `tfsdk:"change_window_cron"`
+	ChangeWindowTZ      types.String      `tfsdk:"change_window_timezone"`
+}
+
+type telemetryModel struct {
+	Endpoint types.String `tfsdk:"endpoint"`
+	Protocol types.String `tfsdk:"protocol"`
+}
+
+type socks5ProxyModel struct {
+	Address  types.String `tfsdk:"address"`
 	Username types.String `tfsdk:"username"`
 	Password types.String `tfsdk:"password"`
 }
 
+// etcdv2ProviderData is handed to resources and data sources once the
+// provider has been configured.
+type etcdv2ProviderData struct {
+	cfg *clientv2.Config
+
+	// leaderElectionGrace bounds how long requests are retried while the
+	// cluster has no leader.
+	leaderElectionGrace time.Duration
+
+	// drift is nil unless report_drift_summary is enabled.
+	drift *driftReport
+
+	// writeAccess is nil unless check_write_permissions is enabled.
+	writeAccess *writeAccess
+
+	// fence is nil unless fencing_workspace is set.
+	fence *applyFence
+
+	// authLocks serializes auth mutations per role and user.
+	authLocks *namedLocks
+
+	// heartbeats keeps etcdv2_heartbeat keys refreshed during the apply.
+	heartbeats *heartbeats
+
+	// snapshot is nil unless snapshot_index_tolerance is set.
+	snapshot *snapshotIndex
+
+	// clusterAlias is the name composite import IDs can refer to the
+	// cluster by.
+	clusterAlias string
+
+	// changeLog is nil unless change_log_key is set.
+	changeLog *changeLog
+
+	// lastWriter is nil unless last_writer_key is set.
+	lastWriter *lastWriter
+
+	// keyPolicy is nil unless allowed_key_prefixes or denied_key_prefixes
+	// is set.
+	keyPolicy *keyPolicy
+
+	// permissionPolicy is nil unless allowed_permission_prefixes is set.
+	permissionPolicy *keyPolicy
+
+	// sensitivePrefixes holds sensitive_key_prefixes.
+	sensitivePrefixes []string
+
+	// changeWindow is nil unless change_window_cron is set.
+	changeWindow *changeWindow
+
+	// missing caches keys data sources found not to exist.
+	missing *missingKeys
+	// endpoints tracks failing endpoints so that they are skipped for a
+	// while.
+	endpoints *endpointHealth
+
+	// clusters holds the clusters of endpoints_by_name by name.
+	clusters map[string]*etcdv2ProviderData
+
+	// telemetry is nil unless the telemetry block is set.
+	telemetry *telemetry
+
+	// claims detects keys planned to be written by more than one resource.
+	claims *keyClaims
+}
+
 func (p *etcdv2Provider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "etcdv2"
 	resp.Version = p.version
@@ -55,6 +172,133 @@ func (p *etcdv2Provider) Schema(ctx context.Context, req provider.SchemaRequest,
 				MarkdownDescription: "The host address of your etcd server",
 				Optional:            true,
 			},
+			"endpoints": schema.ListAttribute{
+				MarkdownDescription: "The addresses of every member of the cluster, so requests fail over to another member when one is down. " +
+					"Conflicts with `host`",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"ca_cert_dir": schema.StringAttribute{
+				MarkdownDescription: "A directory of PEM encoded CA certificates to trust instead of the system roots. " +
+					"The directory is read again when a server certificate fails to verify, so rotated bundles are picked up",
+				Optional: true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificates to trust instead of the system roots, e.g. the `cert_pem` of a `tls_self_signed_cert`. Conflicts with `ca_cert_dir`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"client_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "A PEM encoded client certificate presented to etcd. Requires `client_key_pem`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"client_key_pem": schema.StringAttribute{
+				MarkdownDescription: "The PEM encoded private key of `client_cert_pem`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "A file of PEM encoded CA certificates to trust instead of the system roots. Conflicts with `ca_cert_pem` and `ca_cert_dir`",
+				Optional:            true,
+			},
+			"client_cert_file": schema.StringAttribute{
+				MarkdownDescription: "A file holding the PEM encoded client certificate presented to etcd. Conflicts with `client_cert_pem`",
+				Optional:            true,
+			},
+			"client_key_file": schema.StringAttribute{
+				MarkdownDescription: "A file holding the PEM encoded private key of the client certificate. Conflicts with `client_key_pem`",
+				Optional:            true,
+			},
+			"endpoints_by_name": schema.MapAttribute{
+				MarkdownDescription: "Further clusters by name, each given by its host address. The `etcdv2_keyvalue`, `etcdv2_keyvalues` and `etcdv2_directory` resources " +
+					"and data sources select one with their `cluster` attribute and otherwise use `host` or `endpoints`. The clusters share the rest of the provider configuration, such as credentials and TLS settings",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"telemetry": schema.SingleNestedAttribute{
+				MarkdownDescription: "Export counters of the requests made to etcd, their latencies, errors and leader election retries, " +
+					"so the way Terraform interacts with a cluster can be monitored",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"endpoint": schema.StringAttribute{
+						MarkdownDescription: "The host and port of the collector, e.g. `localhost:8125`",
+						Required:            true,
+					},
+					"protocol": schema.StringAttribute{
+						MarkdownDescription: "The protocol metrics are exported with. Only `statsd` is supported. Defaults to `statsd`",
+						Optional:            true,
+					},
+				},
+			},
+			"socks5_proxy": schema.SingleNestedAttribute{
+				MarkdownDescription: "Dial etcd through a SOCKS5 proxy, such as one forwarded over SSH from a bastion. The proxy resolves the host name",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"address": schema.StringAttribute{
+						MarkdownDescription: "The host and port of the proxy, e.g. `localhost:1080`",
+						Required:            true,
+					},
+					"username": schema.StringAttribute{
+						MarkdownDescription: "The username used to authenticate with the proxy",
+						Optional:            true,
+					},
+					"password": schema.StringAttribute{
+						MarkdownDescription: "The password used to authenticate with the proxy",
+						Optional:            true,
+						Sensitive:           true,
+					},
+				},
+			},
+			"http_proxy": schema.StringAttribute{
+				MarkdownDescription: "The proxy URL for `http://` hosts, e.g. `http://proxy.example.com:3128`. " +
+					"Defaults to the `HTTP_PROXY` environment variable. Conflicts with `socks5_proxy`",
+				Optional: true,
+			},
+			"https_proxy": schema.StringAttribute{
+				MarkdownDescription: "The proxy URL for `https://` hosts, e.g. `http://proxy.example.com:3128`. " +
+					"Defaults to the `HTTPS_PROXY` environment variable. Conflicts with `socks5_proxy`",
+				Optional: true,
+			},
+			"no_proxy": schema.StringAttribute{
+				MarkdownDescription: "A comma separated list of hosts, domains and CIDR ranges reached without `http_proxy` and `https_proxy`, " +
+					"e.g. `localhost,.internal,10.0.0.0/8`. Defaults to the `NO_PROXY` environment variable",
+				Optional: true,
+			},
+			"cluster_alias": schema.StringAttribute{
+				MarkdownDescription: "A name for the cluster. Import IDs of the form `cluster=<alias>,<field>=<value>` must name this alias or the cluster ID, " +
+					"so an identically-named object is not imported from another cluster",
+				Optional: true,
+			},
+			"credentials_from_key": schema.StringAttribute{
+				MarkdownDescription: "An etcd key holding a JSON document with `username` and `password`. " +
+					"The key is read with the rest of the provider configuration, and the credentials found replace `username` and `password`",
+				Optional: true,
+			},
+			"credentials_file": schema.StringAttribute{
+				MarkdownDescription: "A file holding a JSON document with `username` and `password`, used when neither the configuration nor the environment set credentials. " +
+					"Can also be set with ETCDV2_CREDENTIALS_FILE",
+				Optional: true,
+			},
+			"credentials_exec": schema.ListAttribute{
+				MarkdownDescription: "A command and its arguments printing a JSON document with `username` and `password`, " +
+					"used as the last resort when no other source sets credentials",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"leader_election_grace": schema.Int64Attribute{
+				MarkdownDescription: "How long in seconds to keep retrying requests while the cluster is electing a leader. Defaults to 10",
+				Optional:            true,
+			},
+			"path_prefix": schema.StringAttribute{
+				MarkdownDescription: "A path prefix under which a gateway serves the v2 API, e.g. `/etcd` for `https://gw.example.com/etcd/v2`",
+				Optional:            true,
+			},
+			"snapshot_index_tolerance": schema.Int64Attribute{
+				MarkdownDescription: "Pin the etcd index seen by the first directory or queue data source read, and fail later reads " +
+					"returning nodes modified more than this many indexes after it, so a plan does not mix values from different points in time",
+				Optional: true,
+			},
 			"username": schema.StringAttribute{
 				MarkdownDescription: "The username used for authentication",
 				Optional:            true,
@@ -64,6 +308,82 @@ func (p *etcdv2Provider) Schema(ctx context.Context, req provider.SchemaRequest,
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"bearer_token": schema.StringAttribute{
+				MarkdownDescription: "A token sent as `Authorization: Bearer <token>` on every request, for gateways that accept tokens instead of etcd basic auth",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"auth_header": schema.StringAttribute{
+				MarkdownDescription: "A raw `Authorization` header value sent on every request. Conflicts with `bearer_token`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"report_drift_summary": schema.BoolAttribute{
+				MarkdownDescription: "Warn about keyvalue values, role permissions and user roles changed outside of Terraform when they are refreshed, in a single summary counting the drifted values, roles and users and listing each of them",
+				Optional:            true,
+			},
+			"check_write_permissions": schema.BoolAttribute{
+				MarkdownDescription: "Check at plan time that the roles of the provider's user grant write access to every key a resource is about to change, " +
+					"instead of failing part way through the apply. etcd only lets root read roles, so for other users the check is skipped with a warning",
+				Optional: true,
+			},
+			"fencing_workspace": schema.StringAttribute{
+				MarkdownDescription: "Enables apply fencing. A key at `/terraform/locks/<workspace>` is held for the duration of the apply, and operations are refused while another holder exists. " +
+					"The key is refreshed while the apply runs and deleted when it finishes. Should the provider crash, the key is left to expire after `fencing_ttl`",
+				Optional: true,
+			},
+			"allowed_key_prefixes": schema.ListAttribute{
+				MarkdownDescription: "Restrict resources and data sources to keys beneath these prefixes. Keys elsewhere fail at plan time",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"denied_key_prefixes": schema.ListAttribute{
+				MarkdownDescription: "Refuse keys beneath these prefixes, even if they are within `allowed_key_prefixes`. Keys beneath them fail at plan time",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"sensitive_key_prefixes": schema.ListAttribute{
+				MarkdownDescription: "Treat values beneath these prefixes as secrets. The `etcdv2_keyvalue` data source returns them in `sensitive_value` " +
+					"instead of `value`, and the `etcdv2_directory` and `etcdv2_queue` data sources fail rather than return them unmasked",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"change_window_cron": schema.StringAttribute{
+				MarkdownDescription: "A cron expression of minute, hour, day of month, month and day of week matching the minutes changes are allowed in, " +
+					"e.g. `* 2-4 * * SAT` for Saturdays from 02:00 to 04:59. Resources with `respect_change_window` fail to create, update or delete outside of it",
+				Optional: true,
+			},
+			"change_window_timezone": schema.StringAttribute{
+				MarkdownDescription: "The IANA time zone `change_window_cron` is evaluated in, e.g. `Europe/Berlin`. Defaults to `UTC`",
+				Optional:            true,
+			},
+			"allowed_permission_prefixes": schema.ListAttribute{
+				MarkdownDescription: "Only allow `etcdv2_role` to grant access to keys beneath these prefixes. Permissions elsewhere fail at plan time",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"change_log_key": schema.StringAttribute{
+				MarkdownDescription: "A directory the provider appends a JSON record to for every create, update and delete it applies, " +
+					"holding the key, the old and new modified index, a timestamp and the workspace. " +
+					"The workspace is `fencing_workspace` when set, otherwise `TF_WORKSPACE`",
+				Optional: true,
+			},
+			"last_writer_key": schema.StringAttribute{
+				MarkdownDescription: "A directory the provider mirrors every key it creates, updates or deletes into, " +
+					"setting `<last_writer_key>/<key>/_last_writer` to a JSON record of the operation, the modified index, a timestamp, the workspace and the `TFC_RUN_ID` of the run. " +
+					"The workspace is `fencing_workspace` when set, otherwise `TF_WORKSPACE`. " +
+					"A hidden directory such as `/_terraform/last-writer` keeps the stamps out of listings of the root",
+				Optional: true,
+			},
+			"fencing_ttl": schema.Int64Attribute{
+				MarkdownDescription: "The TTL in seconds of the apply fencing key, which is refreshed every third of it. Only matters after a crash, " +
+					"when other applies are refused until the key expires. Defaults to 300",
+				Optional: true,
+			},
+			"follow_redirects": schema.BoolAttribute{
+				MarkdownDescription: "Follow redirects returned by etcd proxies, such as 307 redirects to the leader. Defaults to true",
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -82,6 +402,14 @@ func (p *etcdv2Provider) Configure(ctx context.Context, req provider.ConfigureRe
 		)
 	}
 
+	if config.Endpoints.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoints"),
+			"Unknown etcd API Endpoints",
+			"The provider cannot create the ectdv2 API client as there are unknown endpoints. ",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -89,6 +417,7 @@ func (p *etcdv2Provider) Configure(ctx context.Context, req provider.ConfigureRe
 	host := os.Getenv("ETCDV2_HOST")
 	username := os.Getenv("ETCDV2_USERNAME")
 	password := os.Getenv("ETCDV2_PASSWORD")
+	bearerToken := os.Getenv("ETCDV2_BEARER_TOKEN")
 
 	if !config.Host.IsNull() {
 		host = config.Host.ValueString()
@@ -102,7 +431,108 @@ func (p *etcdv2Provider) Configure(ctx context.Context, req provider.ConfigureRe
 		password = config.Password.ValueString()
 	}
 
-	if host == "" {
+	if !config.BearerToken.IsNull() {
+		bearerToken = config.BearerToken.ValueString()
+	}
+
+	// Credentials are resolved from the first source that sets both a
+	// username and a password: config, environment, file, then exec helper
+	credentialSource := "config"
+	if config.Username.IsNull() || config.Password.IsNull() {
+		credentialSource = "environment"
+	}
+
+	if username == "" || password == "" {
+		credentialsFile := os.Getenv("ETCDV2_CREDENTIALS_FILE")
+		if !config.CredentialsFile.IsNull() {
+			credentialsFile = config.CredentialsFile.ValueString()
+		}
+
+		var creds *keyCredentials
+		var err error
+		switch {
+		case credentialsFile != "":
+			credentialSource = "file"
+			creds, err = credentialsFromFile(credentialsFile)
+		case !config.CredentialsExec.IsNull():
+			var command []string
+			resp.Diagnostics.Append(config.CredentialsExec.ElementsAs(ctx, &command, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			credentialSource = "exec"
+			creds, err = credentialsFromExec(ctx, command)
+		default:
+			credentialSource = ""
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credentials_"+credentialSource),
+				"Unable to resolve etcd credentials",
+				"The credentials "+credentialSource+" could not be read: "+err.Error(),
+			)
+
+			return
+		}
+		if creds != nil {
+			username, password = creds.Username, creds.Password
+		}
+	}
+
+	if credentialSource != "" {
+		tflog.Info(ctx, "Resolved etcd credentials", map[string]interface{}{
+			"source": credentialSource,
+		})
+	}
+
+	authHeader := config.AuthHeader.ValueString()
+	if bearerToken != "" {
+		if authHeader != "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("auth_header"),
+				"Conflicting etcd API authorization",
+				"Only one of bearer_token and auth_header can be set.",
+			)
+
+			return
+		}
+		authHeader = "Bearer " + bearerToken
+	}
+
+	// A username left in the environment without a password is not sent, so
+	// it only conflicts when set in the configuration
+	if authHeader != "" && ((username != "" && password != "") || config.Username.ValueString() != "") {
+		resp.Diagnostics.AddError(
+			"Conflicting etcd API authorization",
+			"A bearer_token or auth_header cannot be combined with username and password, as both are sent in the Authorization header.",
+		)
+
+		return
+	}
+
+	hosts := []string{host}
+	hostPath := func(int) path.Path { return path.Root("host") }
+
+	if !config.Endpoints.IsNull() {
+		if !config.Host.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoints"),
+				"Conflicting etcd API Host",
+				"Only one of host and endpoints can be set.",
+			)
+
+			return
+		}
+
+		resp.Diagnostics.Append(config.Endpoints.ElementsAs(ctx, &hosts, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		hostPath = func(i int) path.Path { return path.Root("endpoints").AtListIndex(i) }
+	}
+
+	if len(hosts) == 0 || hosts[0] == "" {
 		resp.Diagnostics.AddError(
 			"No host detected.",
 			"Ensure a host value is set either via ENV or Config",
@@ -111,11 +541,71 @@ func (p *etcdv2Provider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	httpProxies := map[string]string{
+		"http_proxy":  config.HTTPProxy.ValueString(),
+		"https_proxy": config.HTTPSProxy.ValueString(),
+	}
+	for _, attribute := range []string{"http_proxy", "https_proxy"} {
+		proxy := httpProxies[attribute]
+		if proxy == "" {
+			continue
+		}
+
+		if config.Socks5Proxy != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Conflicting etcd proxies",
+				"Only one of socks5_proxy and "+attribute+" can be set.",
+			)
+
+			return
+		}
+
+		if err := validateProxyURL(proxy); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Invalid etcd HTTP proxy",
+				"The proxy URL could not be used: "+err.Error(),
+			)
+
+			return
+		}
+	}
+
+	// Proxies resolve the host names, which may not resolve locally
+	resolveHosts := config.Socks5Proxy == nil && httpProxies["http_proxy"] == "" && httpProxies["https_proxy"] == ""
+
+	for i, host := range hosts {
+		if hostErr := validateHost(ctx, host, resolveHosts); hostErr != nil {
+			resp.Diagnostics.AddAttributeError(
+				hostPath(i),
+				"Invalid etcd API Host",
+				hostErr.Summary+".\n\n"+hostErr.Remediation+".",
+			)
+
+			return
+		}
+
+		if prefix := config.PathPrefix.ValueString(); prefix != "" {
+			prefixed, err := withPathPrefix(host, prefix)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("path_prefix"),
+					"Invalid etcd API path prefix",
+					"The path prefix could not be applied to the host: "+err.Error(),
+				)
+
+				return
+			}
+			hosts[i] = prefixed
+		}
+	}
+
 	var cfg *clientv2.Config
 
 	if (username != "") && (password != "") {
 		cfg = &clientv2.Config{
-			Endpoints:               []string{host},
+			Endpoints:               hosts,
 			Transport:               clientv2.DefaultTransport,
 			HeaderTimeoutPerRequest: time.Second,
 			Username:                username,
@@ -123,12 +613,210 @@ func (p *etcdv2Provider) Configure(ctx context.Context, req provider.ConfigureRe
 		}
 	} else {
 		cfg = &clientv2.Config{
-			Endpoints:               []string{host},
+			Endpoints:               hosts,
 			Transport:               clientv2.DefaultTransport,
 			HeaderTimeoutPerRequest: time.Second,
 		}
 	}
 
+	caCertPEM, caCertAttr, diags := readPEMAttribute("ca_cert_pem", config.CACertPEM, "ca_cert_file", config.CACertFile)
+	resp.Diagnostics.Append(diags...)
+	clientCertPEM, clientCertAttr, diags := readPEMAttribute("client_cert_pem", config.ClientCertPEM, "client_cert_file", config.ClientCertFile)
+	resp.Diagnostics.Append(diags...)
+	clientKeyPEM, clientKeyAttr, diags := readPEMAttribute("client_key_pem", config.ClientKeyPEM, "client_key_file", config.ClientKeyFile)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if caCertPEM != "" && config.CACertDir.ValueString() != "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(caCertAttr),
+			"Conflicting etcd CA certificates",
+			"Only one of ca_cert_dir and "+caCertAttr+" can be set.",
+		)
+
+		return
+	}
+
+	if (clientCertPEM == "") != (clientKeyPEM == "") {
+		resp.Diagnostics.AddError(
+			"Incomplete etcd client certificate",
+			clientCertAttr+" and "+clientKeyAttr+" must be set together.",
+		)
+
+		return
+	}
+
+	if config.CACertDir.ValueString() != "" || caCertPEM != "" || clientCertPEM != "" || config.Socks5Proxy != nil ||
+		config.HTTPProxy.ValueString() != "" || config.HTTPSProxy.ValueString() != "" || config.NoProxy.ValueString() != "" {
+		transport, err := baseTransport()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create etcdv2 API client",
+				"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+					"etcdv2 Client Error: "+err.Error(),
+			)
+
+			return
+		}
+
+		if dir := config.CACertDir.ValueString(); dir != "" {
+			pool, err := newCADirPool(dir)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("ca_cert_dir"),
+					"Unable to load etcd CA certificates",
+					"The CA certificate directory could not be loaded: "+err.Error(),
+				)
+
+				return
+			}
+			transport.TLSClientConfig = pool.tlsConfig()
+		}
+
+		if caCertPEM != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(caCertPEM)) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(caCertAttr),
+					"Unable to load etcd CA certificates",
+					caCertAttr+" does not hold any PEM encoded certificates.",
+				)
+
+				return
+			}
+			transport.TLSClientConfig = &tls.Config{
+				RootCAs:    pool,
+				MinVersion: tls.VersionTLS12,
+			}
+		}
+
+		if clientCertPEM != "" {
+			cert, err := tls.X509KeyPair([]byte(clientCertPEM), []byte(clientKeyPEM))
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(clientCertAttr),
+					"Unable to load etcd client certificate",
+					"The client certificate and key could not be loaded: "+err.Error(),
+				)
+
+				return
+			}
+			if transport.TLSClientConfig == nil {
+				transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+			}
+			transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+		}
+
+		if proxy := config.Socks5Proxy; proxy != nil {
+			proxyURL, err := socks5ProxyURL(proxy.Address.ValueString(), proxy.Username.ValueString(), proxy.Password.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("socks5_proxy").AtName("address"),
+					"Invalid etcd SOCKS5 proxy",
+					"The proxy address could not be used: "+err.Error(),
+				)
+
+				return
+			}
+			transport.Proxy = http.ProxyURL(proxyURL)
+		} else {
+			transport.Proxy = httpProxyFunc(config.HTTPProxy.ValueString(), config.HTTPSProxy.ValueString(), config.NoProxy.ValueString())
+		}
+
+		cfg.Transport = transport
+	}
+
+	if authHeader != "" {
+		cfg.Transport = &headerTransport{
+			CancelableTransport: cfg.Transport,
+			name:                "Authorization",
+			value:               authHeader,
+		}
+	}
+
+	if !config.FollowRedirects.IsNull() && !config.FollowRedirects.ValueBool() {
+		cfg.CheckRedirect = refuseRedirects
+	}
+
+	endpoints := newEndpointHealth()
+	cfg.Transport = &healthTransport{
+		CancelableTransport: cfg.Transport,
+		health:              endpoints,
+	}
+
+	var metrics *telemetry
+	if config.Telemetry != nil {
+		if protocol := config.Telemetry.Protocol.ValueString(); protocol != "" && protocol != telemetryProtocolStatsd {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("telemetry").AtName("protocol"),
+				"Unsupported etcd telemetry protocol",
+				"The protocol "+protocol+" is not supported, use "+telemetryProtocolStatsd+".",
+			)
+
+			return
+		}
+
+		t, err := newTelemetry(config.Telemetry.Endpoint.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("telemetry").AtName("endpoint"),
+				"Invalid etcd telemetry endpoint",
+				"The telemetry endpoint could not be used: "+err.Error(),
+			)
+
+			return
+		}
+		metrics = t
+
+		cfg.Transport = &telemetryTransport{
+			CancelableTransport: cfg.Transport,
+			telemetry:           metrics,
+		}
+	}
+
+	resp.Diagnostics.Append(checkV2API(ctx, *cfg)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if key := config.CredentialsFromKey.ValueString(); key != "" {
+		creds, err := credentialsFromKey(ctx, *cfg, key)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credentials_from_key"),
+				"Unable to read etcd credentials from key",
+				"The bootstrap client could not load credentials.\n\n"+
+					"etcdv2 Error: "+errorDetail(err),
+			)
+
+			return
+		}
+
+		if authHeader != "" {
+			resp.Diagnostics.AddError(
+				"Conflicting etcd API authorization",
+				"A bearer_token or auth_header cannot be combined with credentials_from_key, as both are sent in the Authorization header.",
+			)
+
+			return
+		}
+
+		cfg.Username = creds.Username
+		cfg.Password = creds.Password
+	}
+
+	if cfg.Username == "" && authHeader == "" {
+		resp.Diagnostics.Append(checkCredentialsRequired(ctx, *cfg)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	//client, err := clientv2.New(cfg)
 	//if err != nil {
 	//	resp.Diagnostics.AddError(
@@ -144,18 +832,189 @@ func (p *etcdv2Provider) Configure(ctx context.Context, req provider.ConfigureRe
 
 	// Example client configuration for data sources and resources
 	//client := http.DefaultClient
-	resp.DataSourceData = cfg
-	resp.ResourceData = cfg
+	data := &etcdv2ProviderData{
+		cfg:                 cfg,
+		leaderElectionGrace: defaultLeaderElectionGrace,
+		authLocks:           newNamedLocks(),
+		heartbeats:          newHeartbeats(),
+		clusterAlias:        config.ClusterAlias.ValueString(),
+		missing:             &missingKeys{},
+		endpoints:           endpoints,
+		telemetry:           metrics,
+		claims:              &keyClaims{},
+	}
+
+	if !config.LeaderElectionGrace.IsNull() {
+		data.leaderElectionGrace = time.Duration(config.LeaderElectionGrace.ValueInt64()) * time.Second
+	}
+
+	if config.ReportDriftSummary.ValueBool() {
+		data.drift = &driftReport{}
+	}
+
+	if config.CheckWritePerms.ValueBool() {
+		data.writeAccess = &writeAccess{tokenAuth: authHeader != ""}
+	}
+
+	if !config.SnapshotTolerance.IsNull() {
+		if config.SnapshotTolerance.ValueInt64() < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("snapshot_index_tolerance"),
+				"Invalid snapshot index tolerance",
+				"snapshot_index_tolerance must not be negative",
+			)
+
+			return
+		}
+		data.snapshot = newSnapshotIndex(uint64(config.SnapshotTolerance.ValueInt64()))
+	}
+
+	if config.FencingWorkspace.ValueString() != "" {
+		ttl := defaultFencingTTL
+		if !config.FencingTTL.IsNull() {
+			if config.FencingTTL.ValueInt64() < 3 {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("fencing_ttl"),
+					"Invalid etcd fencing TTL",
+					"fencing_ttl must be at least 3 seconds so the fencing key can be refreshed before it expires.",
+				)
+
+				return
+			}
+			ttl = time.Duration(config.FencingTTL.ValueInt64()) * time.Second
+		}
+		data.fence = newApplyFence(config.FencingWorkspace.ValueString(), ttl)
+	}
+
+	if !config.AllowedKeyPrefixes.IsNull() || !config.DeniedKeyPrefixes.IsNull() {
+		policy := &keyPolicy{}
+		resp.Diagnostics.Append(config.AllowedKeyPrefixes.ElementsAs(ctx, &policy.allowed, false)...)
+		resp.Diagnostics.Append(config.DeniedKeyPrefixes.ElementsAs(ctx, &policy.denied, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.keyPolicy = policy
+	}
+
+	if spec := config.ChangeWindowCron.ValueString(); spec != "" {
+		timezone := "UTC"
+		if !config.ChangeWindowTZ.IsNull() {
+			timezone = config.ChangeWindowTZ.ValueString()
+		}
+
+		window, err := parseChangeWindow(spec, timezone)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("change_window_cron"),
+				"Invalid etcd change window",
+				"The change window could not be parsed: "+err.Error(),
+			)
+			return
+		}
+		data.changeWindow = window
+	}
+
+	if !config.SensitivePrefixes.IsNull() {
+		resp.Diagnostics.Append(config.SensitivePrefixes.ElementsAs(ctx, &data.sensitivePrefixes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !config.AllowedPermPrefixes.IsNull() {
+		policy := &keyPolicy{}
+		resp.Diagnostics.Append(config.AllowedPermPrefixes.ElementsAs(ctx, &policy.allowed, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.permissionPolicy = policy
+	}
+
+	if config.ChangeLogKey.ValueString() != "" {
+		data.changeLog = newChangeLog(config.ChangeLogKey.ValueString(), config.FencingWorkspace.ValueString())
+	}
+
+	if config.LastWriterKey.ValueString() != "" {
+		data.lastWriter = newLastWriter(config.LastWriterKey.ValueString(), config.FencingWorkspace.ValueString())
+	}
+
+	if !config.EndpointsByName.IsNull() {
+		var named map[string]string
+		resp.Diagnostics.Append(config.EndpointsByName.ElementsAs(ctx, &named, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		data.clusters = make(map[string]*etcdv2ProviderData, len(named))
+		for name, endpoint := range named {
+			if hostErr := validateHost(ctx, endpoint, resolveHosts); hostErr != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("endpoints_by_name").AtMapKey(name),
+					"Invalid etcd API Host",
+					hostErr.Summary+".\n\n"+hostErr.Remediation+".",
+				)
+
+				return
+			}
+
+			if prefix := config.PathPrefix.ValueString(); prefix != "" {
+				prefixed, err := withPathPrefix(endpoint, prefix)
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(
+						path.Root("path_prefix"),
+						"Invalid etcd API path prefix",
+						"The path prefix could not be applied to the host: "+err.Error(),
+					)
+
+					return
+				}
+				endpoint = prefixed
+			}
+
+			data.clusters[name] = data.withEndpoint(name, endpoint)
+		}
+	}
+
+	onShutdown(data.release)
+
+	resp.DataSourceData = data
+	resp.ResourceData = data
 }
 
 func (p *etcdv2Provider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewKeyValueResource,
+		NewDirectoryResource,
+		NewKeyValuesResource,
+		NewRoleResource,
+		NewUserResource,
+		NewHeartbeatResource,
+		NewLeadershipHintResource,
+		NewPrefixCleanupResource,
+		NewRolloutResource,
+		NewPermissionBoundaryResource,
+		NewAuthRestoreResource,
+		NewElectionResource,
 	}
 }
 
 func (p *etcdv2Provider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewKeyValueDataSource,
+		NewKeyAccessDataSource,
+		NewDirectoryDataSource,
+		NewQueueDataSource,
+		NewClusterInfoDataSource,
+		NewAccessMatrixDataSource,
+		NewUnmanagedKeysDataSource,
+		NewReadyDataSource,
+		NewWaitForMembersDataSource,
+		NewAuthSnapshotDataSource,
+		NewRolesWithAccessDataSource,
+		NewValidateDataSource,
+		NewKeyURLDataSource,
+		NewKeyNameDataSource,
+		NewRoleDiffDataSource,
+		NewConnectionTestDataSource,
 	}
 }
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// testAccProtoV6ProviderFactories are used to instantiate a provider during
+// acceptance testing. The factory function will be invoked for every Terraform
+// CLI command executed to create a provider server to which the CLI can
+// reattach.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"etcdv2": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+func testAccPreCheck(t *testing.T) {
+	// The provider is configured for a server started by each test, so
+	// credentials in the environment would only get in the way
+	for _, name := range []string{"ETCDV2_HOST", "ETCDV2_USERNAME", "ETCDV2_PASSWORD", "ETCDV2_BEARER_TOKEN", "ETCDV2_CREDENTIALS_FILE"} {
+		t.Setenv(name, "")
+	}
+
+	t.Cleanup(testAccShutdown)
+}
+
+// testAccShutdown releases the fences and heartbeats held by the provider
+// instances of a test. A real provider process exits after every Terraform
+// command, but the instances of acceptance tests keep running in the test
+// binary, so tests call it where the process would have exited.
+func testAccShutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	Shutdown(ctx)
+}
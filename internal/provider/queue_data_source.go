@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &queueDataSource{}
+	_ datasource.DataSourceWithConfigure = &queueDataSource{}
+)
+
+var queueEntryAttrTypes = map[string]attr.Type{
+	"key":           types.StringType,
+	"value":         types.StringType,
+	"created_index": types.Int64Type,
+}
+
+func NewQueueDataSource() datasource.DataSource {
+	return &queueDataSource{}
+}
+
+type queueDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type queueDataSourceModel struct {
+	Key     types.String `tfsdk:"key"`
+	Limit   types.Int64  `tfsdk:"limit"`
+	Entries types.List   `tfsdk:"entries"`
+}
+
+func (d *queueDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_queue"
+}
+
+func (d *queueDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the in-order keys of an etcd v2 queue directory, oldest first.",
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The queue directory (e.g. '/jobs')",
+				Required:            true,
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "The maximum number of entries to return. Defaults to all entries",
+				Optional:            true,
+			},
+			"entries": schema.ListNestedAttribute{
+				MarkdownDescription: "The queue entries ordered by created index",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							MarkdownDescription: "The key of the entry",
+							Computed:            true,
+						},
+						"value": schema.StringAttribute{
+							MarkdownDescription: "The value of the entry",
+							Computed:            true,
+						},
+						"created_index": schema.Int64Attribute{
+							MarkdownDescription: "The index at which the entry was created",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *queueDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data queueDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(d.provider.checkKeyPolicy("key", data.Key)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	kApi, err := d.provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+
+		return
+	}
+
+	queue, err := kApi.Get(context.Background(), data.Key.ValueString(), &clientv2.GetOptions{Sort: true})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd queue",
+			errorDetail(err),
+		)
+		return
+	}
+
+	if d.provider.snapshot != nil {
+		if err := d.provider.snapshot.check(queue); err != nil {
+			resp.Diagnostics.AddError(
+				"Inconsistent etcd snapshot",
+				"The queue was read too far past the index seen by the first read of this run: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	var children clientv2.Nodes
+	for _, n := range queue.Node.Nodes {
+		if !n.Dir {
+			children = append(children, n)
+		}
+	}
+
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].CreatedIndex < children[j].CreatedIndex
+	})
+
+	if limit := data.Limit.ValueInt64(); !data.Limit.IsNull() && limit >= 0 && int64(len(children)) > limit {
+		children = children[:limit]
+	}
+
+	entries := make([]attr.Value, 0, len(children))
+	for _, n := range children {
+		if d.provider.isSensitiveKey(n.Key) {
+			resp.Diagnostics.Append(sensitiveValueError("key", n.Key))
+			return
+		}
+		entries = append(entries, types.ObjectValueMust(queueEntryAttrTypes, map[string]attr.Value{
+			"key":           types.StringValue(n.Key),
+			"value":         types.StringValue(n.Value),
+			"created_index": types.Int64Value(int64(n.CreatedIndex)),
+		}))
+	}
+
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: queueEntryAttrTypes}, entries)
+	resp.Diagnostics.Append(diags...)
+	data.Entries = list
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *queueDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// memberHealthTimeout bounds the health check made against each member.
+const memberHealthTimeout = 5 * time.Second
+
+// checkHealth fails unless the /health endpoint of client reports healthy.
+func checkHealth(ctx context.Context, client clientv2.Client) error {
+	resp, body, err := client.Do(ctx, &getPathAction{path: "/health"})
+	if err != nil {
+		return err
+	}
+
+	var health struct {
+		Health string `json:"health"`
+	}
+	if resp.StatusCode != http.StatusOK || json.Unmarshal(body, &health) != nil || health.Health != "true" {
+		return fmt.Errorf("cluster is not healthy: %s", body)
+	}
+
+	return nil
+}
+
+// requireHealthyMembers fails unless at least want members of the cluster
+// report healthy, so destructive writes are not made while the cluster is
+// degraded and at risk of losing them.
+func (p *etcdv2ProviderData) requireHealthyMembers(ctx context.Context, want int64) error {
+	client, err := clientv2.New(p.clientConfig())
+	if err != nil {
+		return err
+	}
+
+	members, err := clientv2.NewMembersAPI(client).List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing members: %w", err)
+	}
+
+	var healthy int64
+	for _, member := range members {
+		if p.memberHealthy(ctx, member) {
+			healthy++
+		}
+	}
+
+	if healthy < want {
+		return fmt.Errorf("only %d of %d members are healthy, %d required", healthy, len(members), want)
+	}
+
+	return nil
+}
+
+// memberHealthy checks the health of a single member through its own client
+// URLs. Members that have not started yet have none and count as unhealthy.
+func (p *etcdv2ProviderData) memberHealthy(ctx context.Context, member clientv2.Member) bool {
+	if len(member.ClientURLs) == 0 {
+		return false
+	}
+
+	cfg := *p.cfg
+	cfg.Endpoints = member.ClientURLs
+
+	client, err := clientv2.New(cfg)
+	if err != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, memberHealthTimeout)
+	defer cancel()
+
+	return checkHealth(ctx, client) == nil
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// checkMaxChildren fails if dir would hold more than limit direct children once
+// the names in remove are deleted and those in add are written. Large v2
+// directories make every watch on them expensive, so this is checked before
+// anything is written.
+func checkMaxChildren(ctx context.Context, kApi clientv2.KeysAPI, dir string, limit int64, add, remove map[string]bool) error {
+	children := make(map[string]bool)
+
+	resp, err := kApi.Get(ctx, dir, nil)
+	if err != nil && !clientv2.IsKeyNotFound(err) {
+		return err
+	}
+	if err == nil {
+		base := strings.TrimSuffix(dir, "/") + "/"
+		for _, n := range resp.Node.Nodes {
+			children[strings.TrimPrefix(n.Key, base)] = true
+		}
+	}
+
+	for name := range remove {
+		delete(children, name)
+	}
+	for name := range add {
+		children[name] = true
+	}
+
+	if int64(len(children)) > limit {
+		return fmt.Errorf("directory %s would hold %d entries, more than max_children of %d", dir, len(children), limit)
+	}
+
+	return nil
+}
+
+// topLevelNames returns the direct children of a prefix that the relative
+// names in values live under.
+func topLevelNames(values map[string]string) map[string]bool {
+	names := make(map[string]bool, len(values))
+	for name := range values {
+		names[strings.SplitN(strings.TrimPrefix(name, "/"), "/", 2)[0]] = true
+	}
+
+	return names
+}
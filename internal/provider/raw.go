@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// getPathAction issues a GET for an arbitrary API path through a clientv2
+// client, for endpoints and headers the client library does not expose.
+type getPathAction struct {
+	path string
+}
+
+func (a *getPathAction) HTTPRequest(ep url.URL) *http.Request {
+	ep.Path = path.Join(ep.Path, a.path)
+
+	req, _ := http.NewRequest(http.MethodGet, ep.String(), nil)
+	return req
+}
+
+// clusterHeaders holds the cluster state etcd reports on every keys response.
+type clusterHeaders struct {
+	ClusterID string
+	EtcdIndex uint64
+	RaftIndex uint64
+	RaftTerm  uint64
+}
+
+// readClusterHeaders reads the root of the keyspace and returns the cluster
+// headers from the response.
+func readClusterHeaders(ctx context.Context, client clientv2.Client) (*clusterHeaders, error) {
+	resp, _, err := client.Do(ctx, &getPathAction{path: "/v2/keys/"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &clusterHeaders{
+		ClusterID: resp.Header.Get("X-Etcd-Cluster-Id"),
+		EtcdIndex: headerUint(resp.Header, "X-Etcd-Index"),
+		RaftIndex: headerUint(resp.Header, "X-Raft-Index"),
+		RaftTerm:  headerUint(resp.Header, "X-Raft-Term"),
+	}, nil
+}
+
+func headerUint(h http.Header, name string) uint64 {
+	v, _ := strconv.ParseUint(h.Get(name), 10, 64)
+	return v
+}
+
+// headerRecorder keeps the headers of the last response it passed on, so
+// headers can be read for requests made through clientv2.KeysAPI.
+type headerRecorder struct {
+	clientv2.CancelableTransport
+
+	mu     sync.Mutex
+	header http.Header
+}
+
+func (t *headerRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.CancelableTransport.RoundTrip(req)
+	if err == nil {
+		t.mu.Lock()
+		t.header = resp.Header.Clone()
+		t.mu.Unlock()
+	}
+
+	return resp, err
+}
+
+// headers returns the cluster headers of the last response.
+func (t *headerRecorder) headers() *clusterHeaders {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return &clusterHeaders{
+		ClusterID: t.header.Get("X-Etcd-Cluster-Id"),
+		EtcdIndex: headerUint(t.header, "X-Etcd-Index"),
+		RaftIndex: headerUint(t.header, "X-Raft-Index"),
+		RaftTerm:  headerUint(t.header, "X-Raft-Term"),
+	}
+}
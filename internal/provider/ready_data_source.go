@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &readyDataSource{}
+	_ datasource.DataSourceWithConfigure = &readyDataSource{}
+)
+
+const (
+	// defaultReadyTimeout is used when timeout is not configured.
+	defaultReadyTimeout = 300 * time.Second
+
+	// readyPollInterval is how often readiness is checked again.
+	readyPollInterval = 2 * time.Second
+)
+
+func NewReadyDataSource() datasource.DataSource {
+	return &readyDataSource{}
+}
+
+type readyDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type readyDataSourceModel struct {
+	Timeout types.Int64 `tfsdk:"timeout"`
+	Roles   types.List  `tfsdk:"roles"`
+	Users   types.List  `tfsdk:"users"`
+	Ready   types.Bool  `tfsdk:"ready"`
+}
+
+func (d *readyDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ready"
+}
+
+func (d *readyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Waits for the cluster to report healthy and for auth objects to exist. " +
+			"Reference it with `depends_on` to gate the rest of an apply on the cluster being ready.",
+		Attributes: map[string]schema.Attribute{
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "How long in seconds to wait for the cluster to become ready. Defaults to 300",
+				Optional:            true,
+			},
+			"roles": schema.ListAttribute{
+				MarkdownDescription: "Roles that must exist before the cluster is considered ready",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"users": schema.ListAttribute{
+				MarkdownDescription: "Users that must exist before the cluster is considered ready",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"ready": schema.BoolAttribute{
+				MarkdownDescription: "Always true, since the read fails if the cluster does not become ready in time",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *readyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data readyDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var roles, users []string
+	resp.Diagnostics.Append(data.Roles.ElementsAs(ctx, &roles, false)...)
+	resp.Diagnostics.Append(data.Users.ElementsAs(ctx, &users, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := clientv2.New(d.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	timeout := defaultReadyTimeout
+	if !data.Timeout.IsNull() {
+		timeout = time.Duration(data.Timeout.ValueInt64()) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		err := checkReady(ctx, client, roles, users)
+		if err == nil {
+			break
+		}
+		tflog.Debug(ctx, "etcd cluster not ready", map[string]interface{}{"reason": err.Error()})
+
+		select {
+		case <-ctx.Done():
+			resp.Diagnostics.AddError(
+				"etcd cluster not ready",
+				fmt.Sprintf("The cluster did not become ready within %s: %s", timeout, err),
+			)
+			return
+		case <-time.After(readyPollInterval):
+		}
+	}
+
+	data.Ready = types.BoolValue(true)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// checkReady fails unless /health reports the cluster healthy and all the
+// named roles and users exist.
+func checkReady(ctx context.Context, client clientv2.Client, roles, users []string) error {
+	if err := checkHealth(ctx, client); err != nil {
+		return err
+	}
+
+	rApi := clientv2.NewAuthRoleAPI(client)
+	for _, name := range roles {
+		if _, err := rApi.GetRole(ctx, name); err != nil {
+			return fmt.Errorf("role %s: %w", name, err)
+		}
+	}
+
+	uApi := clientv2.NewAuthUserAPI(client)
+	for _, name := range users {
+		if _, err := uApi.GetUser(ctx, name); err != nil {
+			return fmt.Errorf("user %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (d *readyDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
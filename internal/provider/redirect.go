@@ -0,0 +1,15 @@
+package provider
+
+import (
+	"errors"
+)
+
+// errRedirectRefused is surfaced when etcd, or a proxy in front of it, answers
+// with a redirect while follow_redirects is disabled.
+var errRedirectRefused = errors.New("etcd responded with a redirect, which is refused because follow_redirects is false. " +
+	"Point host at the etcd leader directly or enable follow_redirects")
+
+// refuseRedirects is a clientv2.CheckRedirectFunc that never follows.
+func refuseRedirects(_ int) error {
+	return errRedirectRefused
+}
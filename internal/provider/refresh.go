@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// privateKeyLastRead holds the time a resource was last read from or written
+// to etcd, used to honour refresh_interval.
+const privateKeyLastRead = "last_read"
+
+// privateGetter is satisfied by the private state of resource requests.
+type privateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// privateSetter is satisfied by the private state of resource responses.
+type privateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// readWithin reports whether the resource was last read less than interval
+// ago. A missing or unreadable timestamp counts as stale.
+func readWithin(ctx context.Context, private privateGetter, interval time.Duration) bool {
+	if interval <= 0 {
+		return false
+	}
+
+	raw, diags := private.GetKey(ctx, privateKeyLastRead)
+	if diags.HasError() || len(raw) == 0 {
+		return false
+	}
+
+	var lastRead time.Time
+	if err := json.Unmarshal(raw, &lastRead); err != nil {
+		return false
+	}
+
+	return time.Since(lastRead) < interval
+}
+
+// markRead records now as the time the resource was last read.
+func markRead(ctx context.Context, private privateSetter) diag.Diagnostics {
+	raw, err := json.Marshal(time.Now())
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to record last read time", err.Error())
+		return diags
+	}
+
+	return private.SetKey(ctx, privateKeyLastRead, raw)
+}
@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// renameKey moves a key to newKey with value. etcd v2 has no rename, so the
+// new key is created first (failing if it already exists) and the old key is
+// then removed only if it is still at oldIndex. If the old key changed in the
+// meantime the copy is rolled back, so the cluster never ends up with neither
+// key present and only briefly with both.
+func renameKey(ctx context.Context, kApi clientv2.KeysAPI, oldKey string, oldIndex uint64, newKey, value string) (*clientv2.Node, *clientv2.Node, error) {
+	created, err := kApi.Set(ctx, newKey, value, &clientv2.SetOptions{
+		PrevExist: clientv2.PrevNoExist,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating %s: %w", newKey, err)
+	}
+
+	deleted, err := kApi.Delete(ctx, oldKey, &clientv2.DeleteOptions{
+		PrevIndex: oldIndex,
+	})
+	if err != nil && !clientv2.IsKeyNotFound(err) {
+		_, rollbackErr := kApi.Delete(ctx, newKey, &clientv2.DeleteOptions{
+			PrevIndex: created.Node.ModifiedIndex,
+		})
+		if rollbackErr != nil {
+			return nil, nil, fmt.Errorf("deleting %s: %w (rolling back %s also failed: %s)", oldKey, err, newKey, rollbackErr)
+		}
+		return nil, nil, fmt.Errorf("deleting %s: %w", oldKey, err)
+	}
+
+	var prev *clientv2.Node
+	if deleted != nil {
+		prev = deleted.PrevNode
+	}
+
+	return created.Node, prev, nil
+}
@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"context"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"terraform-provider-etcdv2/pkg/etcdv2ops"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &roleDiffDataSource{}
+	_ datasource.DataSourceWithConfigure = &roleDiffDataSource{}
+)
+
+func NewRoleDiffDataSource() datasource.DataSource {
+	return &roleDiffDataSource{}
+}
+
+type roleDiffDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type roleDiffDataSourceModel struct {
+	Name        types.String          `tfsdk:"name"`
+	Permissions []RolePermissionModel `tfsdk:"permissions"`
+	Exists      types.Bool            `tfsdk:"exists"`
+	ToGrant     types.Set             `tfsdk:"to_grant"`
+	ToRevoke    types.Set             `tfsdk:"to_revoke"`
+}
+
+func (d *roleDiffDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role_diff"
+}
+
+func (d *roleDiffDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	permissionAttributes := func(description string) schema.SetNestedAttribute {
+		return schema.SetNestedAttribute{
+			MarkdownDescription: description,
+			Computed:            true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"key": schema.StringAttribute{
+						MarkdownDescription: "The key or key pattern",
+						Computed:            true,
+					},
+					"access": schema.StringAttribute{
+						MarkdownDescription: "One of `read`, `write` or `readwrite`",
+						Computed:            true,
+					},
+				},
+			},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Compares the permissions a role should have with those it has, " +
+			"so reviews of sensitive roles can show exactly what an apply of `etcdv2_role` would grant and revoke.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the role",
+				Required:            true,
+			},
+			"permissions": schema.SetNestedAttribute{
+				MarkdownDescription: "The key permissions the role should have, as given to `etcdv2_role`",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							MarkdownDescription: "The key or key pattern, where a trailing '*' matches by prefix (e.g. '/foo/*')",
+							Required:            true,
+						},
+						"access": schema.StringAttribute{
+							MarkdownDescription: "One of `read`, `write` or `readwrite`",
+							Required:            true,
+						},
+					},
+				},
+			},
+			"exists": schema.BoolAttribute{
+				MarkdownDescription: "Whether the role exists. Every permission is to be granted to a role that does not",
+				Computed:            true,
+			},
+			"to_grant":  permissionAttributes("The permissions the role lacks"),
+			"to_revoke": permissionAttributes("The permissions the role has but should not"),
+		},
+	}
+}
+
+func (d *roleDiffDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data roleDiffDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, perm := range data.Permissions {
+		switch perm.Access.ValueString() {
+		case accessRead, accessWrite, accessReadWrite:
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("permissions"),
+				"Invalid role permission access",
+				"The access for "+perm.Key.ValueString()+" must be one of read, write or readwrite, got "+perm.Access.ValueString(),
+			)
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new etcd client from config, reading from the leader so a
+	// grant made just before is not missing from the permission list
+	client, err := d.provider.leaderClient(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+
+		return
+	}
+
+	var have clientv2.Permissions
+	role, err := clientv2.NewAuthRoleAPI(client).GetRole(ctx, data.Name.ValueString())
+	switch {
+	case clientv2.IsRoleNotFound(err):
+		data.Exists = types.BoolValue(false)
+	case err != nil:
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd role",
+			errorDetail(err),
+		)
+		return
+	default:
+		data.Exists = types.BoolValue(true)
+		have = role.Permissions
+	}
+
+	want := RoleResourceModel{Permissions: data.Permissions}
+	grant, revoke := etcdv2ops.DiffRole(want.permissions(), have)
+
+	toGrant, diags := permissionSet(ctx, grant)
+	resp.Diagnostics.Append(diags...)
+	data.ToGrant = toGrant
+
+	toRevoke, diags := permissionSet(ctx, revoke)
+	resp.Diagnostics.Append(diags...)
+	data.ToRevoke = toRevoke
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *roleDiffDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
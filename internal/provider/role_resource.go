@@ -0,0 +1,589 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"terraform-provider-etcdv2/pkg/etcdv2ops"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                   = &RoleResource{}
+	_ resource.ResourceWithConfigure      = &RoleResource{}
+	_ resource.ResourceWithImportState    = &RoleResource{}
+	_ resource.ResourceWithValidateConfig = &RoleResource{}
+	_ resource.ResourceWithModifyPlan     = &RoleResource{}
+)
+
+// privateKeyPendingGrants is set in private state while a newly added role
+// has not yet been granted all of its permissions.
+const privateKeyPendingGrants = "pending_grants"
+
+// Values of path_style. etcd matches permission keys literally, so '/foo' and
+// '/foo/' grant access to different keys.
+const (
+	pathStyleExact           = "exact"
+	pathStyleTrailingSlash   = "trailing_slash"
+	pathStyleNoTrailingSlash = "no_trailing_slash"
+)
+
+func NewRoleResource() resource.Resource {
+	return &RoleResource{}
+}
+
+// RoleResource defines the resource implementation
+type RoleResource struct {
+	provider *etcdv2ProviderData
+}
+
+// RoleResourceModel describes the resource data model.
+type RoleResourceModel struct {
+	Name          types.String          `tfsdk:"name"`
+	Permissions   []RolePermissionModel `tfsdk:"permissions"`
+	AdoptExisting types.Bool            `tfsdk:"adopt_existing"`
+	PathStyle     types.String          `tfsdk:"path_style"`
+}
+
+// RolePermissionModel describes a single key permission of a role.
+type RolePermissionModel struct {
+	Key    types.String `tfsdk:"key"`
+	Access types.String `tfsdk:"access"`
+}
+
+func (r *RoleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role"
+}
+
+func (r *RoleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "etcdv2 Role resource",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the role",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Adopt a role that already exists instead of failing, granting and revoking permissions to match the configuration",
+				Optional:            true,
+			},
+			"path_style": schema.StringAttribute{
+				MarkdownDescription: "How permission keys are spelled when granted: `exact` grants them as written, `trailing_slash` appends a '/' " +
+					"and `no_trailing_slash` removes it, leaving keys ending in '*' alone. The configured spelling is kept in state, " +
+					"so directory grants do not show a diff. Defaults to `exact`",
+				Optional: true,
+			},
+			"permissions": schema.SetNestedAttribute{
+				MarkdownDescription: "The key permissions granted to the role",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							MarkdownDescription: "The key or key pattern, where a trailing '*' matches by prefix (e.g. '/foo/*')",
+							Required:            true,
+						},
+						"access": schema.StringAttribute{
+							MarkdownDescription: "One of `read`, `write` or `readwrite`",
+							Required:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *RoleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data RoleResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch data.PathStyle.ValueString() {
+	case "", pathStyleExact, pathStyleTrailingSlash, pathStyleNoTrailingSlash:
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("path_style"),
+			"Invalid role path style",
+			"The path_style must be one of exact, trailing_slash or no_trailing_slash, got "+data.PathStyle.ValueString(),
+		)
+	}
+
+	spelled := make(map[string]string)
+
+	for _, perm := range data.Permissions {
+		if !perm.Key.IsUnknown() && !perm.Key.IsNull() && !data.PathStyle.IsUnknown() {
+			key := normalizePermissionKey(perm.Key.ValueString(), data.PathStyle.ValueString())
+			if other, ok := spelled[key]; ok && other != perm.Key.ValueString() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("permissions"),
+					"Duplicate role permission key",
+					"The keys "+other+" and "+perm.Key.ValueString()+" are both granted as "+key+" with path_style "+data.PathStyle.ValueString()+".",
+				)
+			}
+			spelled[key] = perm.Key.ValueString()
+		}
+
+		if perm.Access.IsUnknown() || perm.Access.IsNull() {
+			continue
+		}
+
+		switch perm.Access.ValueString() {
+		case accessRead, accessWrite, accessReadWrite:
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("permissions"),
+				"Invalid role permission access",
+				"The access for "+perm.Key.ValueString()+" must be one of read, write or readwrite, got "+perm.Access.ValueString(),
+			)
+		}
+	}
+}
+
+func (r *RoleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan RoleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, perm := range plan.Permissions {
+		if perm.Key.IsUnknown() || perm.Key.IsNull() {
+			continue
+		}
+
+		if err := r.provider.checkPermissionPolicy(perm.Key.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("permissions"),
+				"Permission not permitted by provider policy",
+				"The provider configuration restricts the keys roles may grant access to: "+err.Error(),
+			)
+		}
+	}
+}
+
+func (r *RoleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	r.provider = provider
+}
+
+func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RoleResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	client, err := clientv2.New(r.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Retrieve RoleAPI from client
+	rApi := clientv2.NewAuthRoleAPI(client)
+
+	defer r.provider.lockRole(data.Name.ValueString())()
+
+	err = rApi.AddRole(ctx, data.Name.ValueString())
+	if isAuthExist(err) && data.AdoptExisting.ValueBool() {
+		role, err := rApi.GetRole(ctx, data.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read existing etcd role",
+				errorDetail(err),
+			)
+			return
+		}
+
+		existing := RoleResourceModel{Name: data.Name}
+		existing.setPermissions(role.Permissions)
+
+		resp.Diagnostics.Append(data.updatePermissions(ctx, rApi, &existing)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcd role",
+			errorDetail(err),
+		)
+		return
+	}
+
+	// Until every grant succeeds the role is only partially created
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateKeyPendingGrants, []byte("true"))...)
+
+	_, err = etcdv2ops.GrantRole(ctx, rApi, data.Name.ValueString(), data.permissions())
+	if err != nil {
+		// Roll back so a retry does not fail with the role already existing
+		rollbackErr := rApi.RemoveRole(ctx, data.Name.ValueString())
+		if rollbackErr == nil {
+			resp.Diagnostics.AddError(
+				"Unable to Grant etcd role permissions",
+				errorDetail(err),
+			)
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		// Otherwise keep what was created without tainting it. Terraform
+		// only accepts the planned permissions in state, so those are saved
+		// and the next refresh finds the missing ones for the next apply
+		resp.Diagnostics.AddWarning(
+			"Unable to Grant etcd role permissions",
+			"The role "+data.Name.ValueString()+" was created, but not all of its permissions could be granted and the role "+
+				"could not be removed again. It was saved to state, the next plan shows the missing permissions "+
+				"and the next apply grants them.\n\n"+
+				"etcdv2 Error: "+errorDetail(err)+"\n\n"+
+				"etcdv2 Rollback Error: "+errorDetail(rollbackErr),
+		)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateKeyPendingGrants, []byte("false"))...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RoleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data RoleResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new etcd client from config, reading from the leader so a
+	// grant made just before is not missing from the permission list
+	client, err := r.provider.leaderClient(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Retrieve RoleAPI from client
+	rApi := clientv2.NewAuthRoleAPI(client)
+
+	role, err := rApi.GetRole(ctx, data.Name.ValueString())
+	if clientv2.IsRoleNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd role",
+			errorDetail(err),
+		)
+		return
+	}
+
+	pending, diags := req.Private.GetKey(ctx, privateKeyPendingGrants)
+	resp.Diagnostics.Append(diags...)
+
+	if string(pending) == "true" {
+		tflog.Warn(ctx, "etcd role was only partially created, the next apply grants the missing permissions", map[string]interface{}{
+			"role": data.Name.ValueString(),
+		})
+	}
+
+	// Permissions missing from a partially created role are not drift
+	if r.provider.drift != nil && string(pending) != "true" {
+		// Granted and revoked relative to state, i.e. by someone else
+		revoked, granted := etcdv2ops.DiffRole(data.permissions(), role.Permissions)
+		if len(granted.KV.Read)+len(granted.KV.Write)+len(revoked.KV.Read)+len(revoked.KV.Write) > 0 {
+			resp.Diagnostics.Append(r.provider.drift.recordPermissions(data.Name.ValueString(), granted, revoked)...)
+		}
+	}
+
+	data.setPermissions(role.Permissions)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data RoleResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	client, err := clientv2.New(r.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Retrieve RoleAPI from client
+	rApi := clientv2.NewAuthRoleAPI(client)
+
+	defer r.provider.lockRole(data.Name.ValueString())()
+
+	// Diff against the keys as etcd spells them, which the state may not
+	// when path_style normalizes them
+	role, err := rApi.GetRole(ctx, data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd role",
+			errorDetail(err),
+		)
+		return
+	}
+
+	existing := RoleResourceModel{Name: data.Name}
+	existing.setPermissions(role.Permissions)
+
+	resp.Diagnostics.Append(data.updatePermissions(ctx, rApi, &existing)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every permission is granted now, even if Create only got part way
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateKeyPendingGrants, []byte("false"))...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RoleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data RoleResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	client, err := clientv2.New(r.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Retrieve RoleAPI from client
+	rApi := clientv2.NewAuthRoleAPI(client)
+
+	defer r.provider.lockRole(data.Name.ValueString())()
+
+	err = rApi.RemoveRole(ctx, data.Name.ValueString())
+	if err != nil && !clientv2.IsRoleNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error when trying to Delete etcd role",
+			errorDetail(err),
+		)
+		return
+	}
+}
+
+func (r *RoleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, diags := r.provider.importID(ctx, req.ID, "name")
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), id)...)
+}
+
+// updatePermissions revokes and grants whatever differs between the
+// permissions in have and those of the model.
+func (m *RoleResourceModel) updatePermissions(ctx context.Context, rApi clientv2.AuthRoleAPI, have *RoleResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if err := etcdv2ops.SyncRole(ctx, rApi, m.Name.ValueString(), m.permissions(), have.permissions()); err != nil {
+		diags.AddError(
+			"Unable to Update etcd role permissions",
+			errorDetail(err),
+		)
+	}
+
+	return diags
+}
+
+// permissions returns the permissions the model grants.
+func (m *RoleResourceModel) permissions() clientv2.Permissions {
+	var perms clientv2.Permissions
+
+	for _, perm := range m.Permissions {
+		key := normalizePermissionKey(perm.Key.ValueString(), m.PathStyle.ValueString())
+
+		switch perm.Access.ValueString() {
+		case accessRead:
+			perms.KV.Read = append(perms.KV.Read, key)
+		case accessWrite:
+			perms.KV.Write = append(perms.KV.Write, key)
+		case accessReadWrite:
+			perms.KV.Read = append(perms.KV.Read, key)
+			perms.KV.Write = append(perms.KV.Write, key)
+		}
+	}
+
+	return perms
+}
+
+// setPermissions replaces the model permissions with those etcd reports.
+// Keys granted in the normalized spelling of a model key keep the model's
+// spelling.
+func (m *RoleResourceModel) setPermissions(perms clientv2.Permissions) {
+	spelling := make(map[string]string)
+	for _, perm := range m.Permissions {
+		spelling[normalizePermissionKey(perm.Key.ValueString(), m.PathStyle.ValueString())] = perm.Key.ValueString()
+	}
+	spell := func(key string) string {
+		if s, ok := spelling[key]; ok {
+			return s
+		}
+		return key
+	}
+
+	read := make(map[string]bool)
+	write := make(map[string]bool)
+	keys := make(map[string]bool)
+
+	for _, key := range perms.KV.Read {
+		key = spell(key)
+		read[key] = true
+		keys[key] = true
+	}
+	for _, key := range perms.KV.Write {
+		key = spell(key)
+		write[key] = true
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var permissions []RolePermissionModel
+	for _, key := range sorted {
+		access := accessReadWrite
+		switch {
+		case !write[key]:
+			access = accessRead
+		case !read[key]:
+			access = accessWrite
+		}
+
+		permissions = append(permissions, RolePermissionModel{
+			Key:    types.StringValue(key),
+			Access: types.StringValue(access),
+		})
+	}
+
+	// Keep an unset attribute unset when the role has no permissions
+	if len(permissions) == 0 && m.Permissions == nil {
+		return
+	}
+
+	m.Permissions = permissions
+}
+
+// normalizePermissionKey spells key according to a path_style. Patterns
+// ending in '*' and the root are left as they are.
+func normalizePermissionKey(key, style string) string {
+	if strings.HasSuffix(key, "*") || key == "/" {
+		return key
+	}
+
+	switch style {
+	case pathStyleTrailingSlash:
+		return strings.TrimRight(key, "/") + "/"
+	case pathStyleNoTrailingSlash:
+		return strings.TrimRight(key, "/")
+	}
+
+	return key
+}
@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"terraform-provider-etcdv2/internal/etcdv2mock"
+	"terraform-provider-etcdv2/internal/testetcd"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccRoleResource(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: etcd.ProviderConfig() + testAccRoleResourceConfig("readwrite"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("etcdv2_role.test", "name", "app"),
+					resource.TestCheckResourceAttr("etcdv2_role.test", "permissions.#", "1"),
+					testAccCheckEtcdRole(etcd, "app", []string{"/app/*"}, []string{"/app/*"}),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:                         "etcdv2_role.test",
+				ImportState:                          true,
+				ImportStateId:                        "app",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+			},
+			// Update and Read testing
+			{
+				Config: etcd.ProviderConfig() + testAccRoleResourceConfig("read"),
+				Check:  testAccCheckEtcdRole(etcd, "app", []string{"/app/*"}, []string{}),
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := etcd.Role("app"); ok {
+				return fmt.Errorf("role app still exists")
+			}
+
+			return nil
+		},
+	})
+}
+
+// TestAccRoleResource_batchedGrant grants the permissions of a new role in one
+// request per permission type.
+func TestAccRoleResource_batchedGrant(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: etcd.ProviderConfig() + testAccRoleResourceBatchConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckEtcdRole(etcd, "app",
+						[]string{"/app/*", "/config/*", "/shared/*"},
+						[]string{"/app/*", "/events/*", "/queue/*"},
+					),
+					func(*terraform.State) error {
+						// The role is added, then granted readwrite, read and
+						// write permissions
+						if n := etcd.Requests("PUT", "/v2/auth/roles/app"); n != 4 {
+							return fmt.Errorf("role app was written by %d requests, want 4", n)
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// TestAccRoleResource_grantRollback fails the second grant of a new role,
+// which is removed again so the retry can create it.
+func TestAccRoleResource_grantRollback(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   func() { testAccFailRoleGrant(etcd, "app", 2) },
+				Config:      etcd.ProviderConfig() + testAccRoleResourceBatchConfig,
+				ExpectError: regexp.MustCompile("Unable to Grant etcd role permissions"),
+			},
+			{
+				PreConfig: func() {
+					if _, ok := etcd.Role("app"); ok {
+						t.Error("the partially granted role was not removed")
+					}
+				},
+				Config: etcd.ProviderConfig() + testAccRoleResourceBatchConfig,
+				Check: testAccCheckEtcdRole(etcd, "app",
+					[]string{"/app/*", "/config/*", "/shared/*"},
+					[]string{"/app/*", "/events/*", "/queue/*"},
+				),
+			},
+		},
+	})
+}
+
+// TestAccRoleResource_failedRollback fails the second grant of a new role and
+// removing it again. The role is kept with the permissions granted so far, and
+// the next apply grants the rest.
+func TestAccRoleResource_failedRollback(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					testAccFailRoleGrant(etcd, "app", 2)
+					etcd.InjectAuthError("DELETE", "roles/app", http.StatusInternalServerError, "auth: internal error")
+				},
+				Config: etcd.ProviderConfig() + testAccRoleResourceBatchConfig,
+				Check:  testAccCheckEtcdRole(etcd, "app", []string{"/app/*"}, []string{"/app/*"}),
+				// The refresh finds the permissions still missing
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: etcd.ProviderConfig() + testAccRoleResourceBatchConfig,
+				Check: testAccCheckEtcdRole(etcd, "app",
+					[]string{"/app/*", "/config/*", "/shared/*"},
+					[]string{"/app/*", "/events/*", "/queue/*"},
+				),
+			},
+		},
+	})
+}
+
+const testAccRoleResourceBatchConfig = `
+resource "etcdv2_role" "test" {
+  name = "app"
+
+  permissions = [
+    { key = "/app/*", access = "readwrite" },
+    { key = "/config/*", access = "read" },
+    { key = "/shared/*", access = "read" },
+    { key = "/events/*", access = "write" },
+    { key = "/queue/*", access = "write" },
+  ]
+}
+`
+
+// testAccFailRoleGrant fails the grant-th permission grant to the next role
+// name created.
+func testAccFailRoleGrant(etcd *testetcd.Server, name string, grant int) {
+	etcd.Inject(etcdv2mock.Fault{
+		Method: http.MethodPut,
+		Path:   "/v2/auth/roles/" + name,
+		Status: http.StatusInternalServerError,
+		Body:   etcdv2mock.AuthError{Message: "auth: internal error"},
+		Times:  1,
+		// The first request adds the role
+		After: grant,
+	})
+}
+
+func testAccRoleResourceConfig(access string) string {
+	return fmt.Sprintf(`
+resource "etcdv2_role" "test" {
+  name = "app"
+
+  permissions = [
+    {
+      key    = "/app/*"
+      access = %[1]q
+    },
+  ]
+}
+`, access)
+}
+
+// testAccCheckEtcdRole checks the permissions the server holds for role.
+func testAccCheckEtcdRole(etcd *testetcd.Server, name string, read, write []string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		role, ok := etcd.Role(name)
+		if !ok {
+			return fmt.Errorf("role %s does not exist", name)
+		}
+
+		kv := role.Permissions.KV
+		if !reflect.DeepEqual(kv.Read, read) || !reflect.DeepEqual(kv.Write, write) {
+			return fmt.Errorf("role %s grants read %v and write %v, want read %v and write %v", name, kv.Read, kv.Write, read, write)
+		}
+
+		return nil
+	}
+}
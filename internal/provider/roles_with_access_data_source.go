@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &rolesWithAccessDataSource{}
+	_ datasource.DataSourceWithConfigure = &rolesWithAccessDataSource{}
+)
+
+func NewRolesWithAccessDataSource() datasource.DataSource {
+	return &rolesWithAccessDataSource{}
+}
+
+type rolesWithAccessDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type rolesWithAccessDataSourceModel struct {
+	Key    types.String `tfsdk:"key"`
+	Access types.String `tfsdk:"access"`
+	Roles  types.List   `tfsdk:"roles"`
+}
+
+func (d *rolesWithAccessDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_roles_with_access"
+}
+
+func (d *rolesWithAccessDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Returns the names of the roles that can read or write a key, answering who can touch the key for audits.",
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The key to check access for (e.g. '/foo/bar')",
+				Required:            true,
+			},
+			"access": schema.StringAttribute{
+				MarkdownDescription: "Only return roles with this access to the key, one of `read`, `write` or `readwrite` for both. " +
+					"By default roles with either are returned",
+				Optional: true,
+			},
+			"roles": schema.ListAttribute{
+				MarkdownDescription: "The names of the roles with access to the key, sorted",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *rolesWithAccessDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data rolesWithAccessDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch data.Access.ValueString() {
+	case "", accessRead, accessWrite, accessReadWrite:
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("access"),
+			"Invalid etcd access",
+			"access must be one of "+accessRead+", "+accessWrite+" or "+accessReadWrite+".",
+		)
+		return
+	}
+
+	client, err := clientv2.New(d.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+
+		return
+	}
+
+	access, err := rolesWithAccess(ctx, clientv2.NewAuthRoleAPI(client), data.Key.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd role permissions",
+			errorDetail(err),
+		)
+		return
+	}
+
+	roles := []string{}
+	for _, a := range access {
+		switch data.Access.ValueString() {
+		case accessRead:
+			if !a.Read {
+				continue
+			}
+		case accessWrite:
+			if !a.Write {
+				continue
+			}
+		case accessReadWrite:
+			if !a.Read || !a.Write {
+				continue
+			}
+		}
+		roles = append(roles, a.Role)
+	}
+	sort.Strings(roles)
+
+	list, diags := types.ListValueFrom(ctx, types.StringType, roles)
+	resp.Diagnostics.Append(diags...)
+	data.Roles = list
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *rolesWithAccessDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
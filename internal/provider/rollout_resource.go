@@ -0,0 +1,374 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// defaultRolloutTimeout is used when timeout is not configured.
+	defaultRolloutTimeout = 300 * time.Second
+
+	// rolloutPollInterval is the pause between confirmation key checks.
+	rolloutPollInterval = time.Second
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource               = &RolloutResource{}
+	_ resource.ResourceWithConfigure  = &RolloutResource{}
+	_ resource.ResourceWithModifyPlan = &RolloutResource{}
+)
+
+func NewRolloutResource() resource.Resource {
+	return &RolloutResource{}
+}
+
+// RolloutResource defines the resource implementation
+type RolloutResource struct {
+	provider *etcdv2ProviderData
+}
+
+// RolloutResourceModel describes the resource data model.
+type RolloutResourceModel struct {
+	Key              types.String `tfsdk:"key"`
+	Value            types.String `tfsdk:"value"`
+	ConfirmKey       types.String `tfsdk:"confirm_key"`
+	ConfirmValue     types.String `tfsdk:"confirm_value"`
+	Timeout          types.Int64  `tfsdk:"timeout"`
+	PromoteOnTimeout types.Bool   `tfsdk:"promote_on_timeout"`
+	ModifiedIndex    types.Int64  `tfsdk:"modified_index"`
+}
+
+func (r *RolloutResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rollout"
+}
+
+func (r *RolloutResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Rolls out a value in two phases. The value is written to `<key>.next`, and once consumers write the confirmation key " +
+			"it is promoted to `key` and both staging keys are removed.",
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The key the value is promoted to (e.g. '/app/config')",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "The value to roll out",
+				Required:            true,
+			},
+			"confirm_key": schema.StringAttribute{
+				MarkdownDescription: "The key consumers write once they accept the staged value. Defaults to `<key>.confirmed`",
+				Optional:            true,
+			},
+			"confirm_value": schema.StringAttribute{
+				MarkdownDescription: "The value the confirmation key must hold, e.g. a version. Defaults to accepting any value",
+				Optional:            true,
+			},
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "How long in seconds to wait for the confirmation key. Defaults to 300",
+				Optional:            true,
+			},
+			"promote_on_timeout": schema.BoolAttribute{
+				MarkdownDescription: "Promote the value when the timeout passes without confirmation instead of failing the apply",
+				Optional:            true,
+			},
+			"modified_index": schema.Int64Attribute{
+				MarkdownDescription: "The index at which the value was promoted",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *RolloutResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(r.provider.checkPlannedKeys(ctx, req.Plan, "key", "confirm_key")...)
+}
+
+func (r *RolloutResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	r.provider = provider
+}
+
+func (r *RolloutResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RolloutResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.rollout(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RolloutResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data RolloutResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new etcd client from config
+	kApi, err := r.provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	keyvalue, err := kApi.Get(ctx, data.Key.ValueString(), nil)
+	if clientv2.IsKeyNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd rollout",
+			errorDetail(err),
+		)
+		return
+	}
+
+	// A changed value is rolled out again on the next apply
+	data.Value = types.StringValue(keyvalue.Node.Value)
+	data.ModifiedIndex = types.Int64Value(int64(keyvalue.Node.ModifiedIndex))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RolloutResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data RolloutResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.rollout(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RolloutResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data RolloutResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	kApi, err := r.provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	for _, key := range []string{data.Key.ValueString(), data.nextKey(), data.confirmKey()} {
+		deleted, err := kApi.Delete(ctx, key, nil)
+		if err != nil && !clientv2.IsKeyNotFound(err) {
+			resp.Diagnostics.AddError(
+				"Error when trying to Delete etcd rollout",
+				errorDetail(err),
+			)
+			return
+		}
+		if err == nil && key == data.Key.ValueString() {
+			resp.Diagnostics.Append(r.provider.recordChange(ctx, changeDelete, key, prevIndex(deleted.PrevNode), deleted.Index)...)
+		}
+	}
+}
+
+// rollout stages the value, waits for it to be confirmed and promotes it.
+// When the rollout fails the staged value is removed again.
+func (r *RolloutResource) rollout(ctx context.Context, data *RolloutResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		diags.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return diags
+	}
+
+	// Create new etcd client from config
+	kApi, err := r.provider.keysAPI()
+	if err != nil {
+		diags.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return diags
+	}
+
+	// A confirmation left from an earlier rollout must not count for this one
+	if _, err := kApi.Delete(ctx, data.confirmKey(), nil); err != nil && !clientv2.IsKeyNotFound(err) {
+		diags.AddError(
+			"Unable to Reset etcd rollout confirmation",
+			errorDetail(err),
+		)
+		return diags
+	}
+
+	if _, err := kApi.Set(ctx, data.nextKey(), data.Value.ValueString(), nil); err != nil {
+		diags.AddError(
+			"Unable to Stage etcd rollout",
+			errorDetail(err),
+		)
+		return diags
+	}
+
+	confirmed, err := waitForConfirmation(ctx, kApi, data)
+	if err == nil && !confirmed && !data.PromoteOnTimeout.ValueBool() {
+		err = fmt.Errorf("no confirmation was written to %s within %s", data.confirmKey(), data.timeout())
+	}
+	if err != nil {
+		diags.AddError(
+			"etcd rollout was not confirmed",
+			"The staged value at "+data.nextKey()+" was removed.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		if _, cleanupErr := kApi.Delete(ctx, data.nextKey(), nil); cleanupErr != nil && !clientv2.IsKeyNotFound(cleanupErr) {
+			diags.AddWarning(
+				"Unable to remove staged etcd rollout value",
+				errorDetail(cleanupErr),
+			)
+		}
+		return diags
+	}
+
+	promoted, err := kApi.Set(ctx, data.Key.ValueString(), data.Value.ValueString(), nil)
+	if err != nil {
+		diags.AddError(
+			"Unable to Promote etcd rollout",
+			errorDetail(err),
+		)
+		return diags
+	}
+
+	operation := changeUpdate
+	if promoted.PrevNode == nil {
+		operation = changeCreate
+	}
+	diags.Append(r.provider.recordChange(ctx, operation, promoted.Node.Key, prevIndex(promoted.PrevNode), promoted.Node.ModifiedIndex)...)
+
+	data.ModifiedIndex = types.Int64Value(int64(promoted.Node.ModifiedIndex))
+
+	for _, key := range []string{data.nextKey(), data.confirmKey()} {
+		if _, err := kApi.Delete(ctx, key, nil); err != nil && !clientv2.IsKeyNotFound(err) {
+			diags.AddWarning(
+				"Unable to clean up etcd rollout",
+				"The value was promoted but "+key+" could not be removed.\n\n"+
+					"etcdv2 Error: "+errorDetail(err),
+			)
+		}
+	}
+
+	return diags
+}
+
+// waitForConfirmation polls the confirmation key until it holds the expected
+// value or the timeout passes.
+func waitForConfirmation(ctx context.Context, kApi clientv2.KeysAPI, data *RolloutResourceModel) (bool, error) {
+	deadline := time.Now().Add(data.timeout())
+
+	for {
+		confirmation, err := kApi.Get(ctx, data.confirmKey(), &clientv2.GetOptions{Quorum: true})
+		if err != nil && !clientv2.IsKeyNotFound(err) {
+			return false, err
+		}
+		if err == nil && (data.ConfirmValue.IsNull() || confirmation.Node.Value == data.ConfirmValue.ValueString()) {
+			return true, nil
+		}
+
+		if time.Now().Add(rolloutPollInterval).After(deadline) {
+			return false, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(rolloutPollInterval):
+		}
+	}
+}
+
+// nextKey returns the key the value is staged at.
+func (m *RolloutResourceModel) nextKey() string {
+	return m.Key.ValueString() + ".next"
+}
+
+// confirmKey returns the key consumers confirm the staged value with.
+func (m *RolloutResourceModel) confirmKey() string {
+	if !m.ConfirmKey.IsNull() {
+		return m.ConfirmKey.ValueString()
+	}
+
+	return m.Key.ValueString() + ".confirmed"
+}
+
+// timeout returns how long to wait for confirmation.
+func (m *RolloutResourceModel) timeout() time.Duration {
+	if m.Timeout.IsNull() {
+		return defaultRolloutTimeout
+	}
+
+	return time.Duration(m.Timeout.ValueInt64()) * time.Second
+}
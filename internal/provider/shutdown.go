@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"sync"
+)
+
+// shutdownHooks run once the provider server has stopped serving, releasing
+// what the provider held in etcd for the duration of the apply.
+var shutdownHooks struct {
+	mu    sync.Mutex
+	hooks []func(context.Context)
+}
+
+// onShutdown registers hook to run on Shutdown.
+func onShutdown(hook func(context.Context)) {
+	shutdownHooks.mu.Lock()
+	defer shutdownHooks.mu.Unlock()
+
+	shutdownHooks.hooks = append(shutdownHooks.hooks, hook)
+}
+
+// Shutdown runs the registered shutdown hooks. It is called after the provider
+// server returns, which Terraform triggers when it is done with the provider.
+func Shutdown(ctx context.Context) {
+	shutdownHooks.mu.Lock()
+	hooks := shutdownHooks.hooks
+	shutdownHooks.hooks = nil
+	shutdownHooks.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(ctx)
+	}
+}
+
+// release deletes the etcdv2_heartbeat keys and gives up the apply fence of
+// the provider and of every cluster of endpoints_by_name.
+func (p *etcdv2ProviderData) release(ctx context.Context) {
+	if p.heartbeats != nil {
+		p.heartbeats.release(ctx)
+	}
+	if p.fence != nil {
+		p.fence.release(ctx)
+	}
+
+	for _, cluster := range p.clusters {
+		cluster.release(ctx)
+	}
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// snapshotIndex pins the etcd index seen by the first multi-key data source
+// read of a run, so later reads can be checked against it and a single plan
+// does not mix values from different points in time.
+type snapshotIndex struct {
+	tolerance uint64
+
+	mu    sync.Mutex
+	index uint64
+}
+
+func newSnapshotIndex(tolerance uint64) *snapshotIndex {
+	return &snapshotIndex{tolerance: tolerance}
+}
+
+// check records resp.Index on the first call. Later calls fail if any node in
+// resp was modified more than tolerance indexes after the recorded one. Writes
+// elsewhere in the keyspace advance the index too, so only the nodes that were
+// read are compared.
+func (s *snapshotIndex) check(resp *clientv2.Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.index == 0 {
+		s.index = resp.Index
+		return nil
+	}
+
+	if newest := newestIndex(resp.Node); newest > s.index+s.tolerance {
+		return fmt.Errorf("%s was modified at index %d, more than %d after the snapshot index %d", resp.Node.Key, newest, s.tolerance, s.index)
+	}
+
+	return nil
+}
+
+// newestIndex returns the highest ModifiedIndex in the subtree rooted at node.
+func newestIndex(node *clientv2.Node) uint64 {
+	if node == nil {
+		return 0
+	}
+
+	newest := node.ModifiedIndex
+	for _, n := range node.Nodes {
+		if i := newestIndex(n); i > newest {
+			newest = i
+		}
+	}
+
+	return newest
+}
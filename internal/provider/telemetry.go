@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+const (
+	// telemetryProtocolStatsd sends metrics as statsd lines over UDP.
+	telemetryProtocolStatsd = "statsd"
+
+	// telemetryPrefix is prepended to every metric name.
+	telemetryPrefix = "etcdv2."
+)
+
+// telemetry exports counters and timings describing how the provider uses
+// etcd. Metrics are sent over UDP without waiting for a reply, so a missing
+// collector never slows down or fails an operation.
+type telemetry struct {
+	conn net.Conn
+}
+
+func newTelemetry(endpoint string) (*telemetry, error) {
+	conn, err := net.Dial("udp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &telemetry{conn: conn}, nil
+}
+
+// count increments the counter name by one.
+func (t *telemetry) count(name string) {
+	t.send(fmt.Sprintf("%s%s:1|c", telemetryPrefix, name))
+}
+
+// timing records a duration for name in milliseconds.
+func (t *telemetry) timing(name string, d time.Duration) {
+	t.send(fmt.Sprintf("%s%s:%d|ms", telemetryPrefix, name, d.Milliseconds()))
+}
+
+func (t *telemetry) send(line string) {
+	// Metrics are best effort, so write errors are ignored
+	_, _ = t.conn.Write([]byte(line))
+}
+
+// telemetryTransport counts every request to etcd along with its latency, and
+// the requests that got no response or a server error.
+type telemetryTransport struct {
+	clientv2.CancelableTransport
+
+	telemetry *telemetry
+}
+
+func (t *telemetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.CancelableTransport.RoundTrip(req)
+
+	t.telemetry.count("requests")
+	t.telemetry.timing("request_latency", time.Since(start))
+	if err != nil || resp.StatusCode/100 == 5 {
+		t.telemetry.count("request_errors")
+	}
+
+	return resp, err
+}
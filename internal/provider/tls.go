@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// caDirPool is a trust pool loaded from every PEM file in a directory. The
+// directory is read again when a server certificate fails to verify, so CA
+// bundles rotated on disk are picked up without restarting the provider.
+type caDirPool struct {
+	dir string
+
+	mu   sync.RWMutex
+	pool *x509.CertPool
+}
+
+func newCADirPool(dir string) (*caDirPool, error) {
+	pool, err := loadCADir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &caDirPool{dir: dir, pool: pool}, nil
+}
+
+// loadCADir reads every .pem and .crt file in dir into a new pool.
+func loadCADir(dir string) (*x509.CertPool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	loaded := 0
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".pem" && ext != ".crt") {
+			continue
+		}
+
+		pem, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if pool.AppendCertsFromPEM(pem) {
+			loaded++
+		}
+	}
+
+	if loaded == 0 {
+		return nil, fmt.Errorf("no PEM certificates found in %s", dir)
+	}
+
+	return pool, nil
+}
+
+// verifyConnection verifies the server chain against the pool, reloading the
+// directory once if verification fails.
+func (p *caDirPool) verifyConnection(cs tls.ConnectionState) error {
+	err := p.verify(cs)
+	if err == nil {
+		return nil
+	}
+
+	pool, loadErr := loadCADir(p.dir)
+	if loadErr != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.pool = pool
+	p.mu.Unlock()
+
+	return p.verify(cs)
+}
+
+func (p *caDirPool) verify(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("server presented no certificates")
+	}
+
+	p.mu.RLock()
+	roots := p.pool
+	p.mu.RUnlock()
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       cs.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// tlsConfig returns a client TLS configuration that trusts the certificates in
+// the pool instead of the system roots.
+func (p *caDirPool) tlsConfig() *tls.Config {
+	return &tls.Config{
+		// Verification is done by VerifyConnection against the reloadable pool
+		InsecureSkipVerify: true,
+		VerifyConnection:   p.verifyConnection,
+		MinVersion:         tls.VersionTLS12,
+	}
+}
+
+// readPEMAttribute returns the PEM held by the attribute pemAttr or read from
+// the file named by fileAttr, along with the attribute it came from, so later
+// diagnostics point at what the user set.
+func readPEMAttribute(pemAttr string, pemValue types.String, fileAttr string, fileValue types.String) (string, string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if fileValue.ValueString() == "" {
+		return pemValue.ValueString(), pemAttr, diags
+	}
+
+	if pemValue.ValueString() != "" {
+		diags.AddAttributeError(
+			path.Root(fileAttr),
+			"Conflicting etcd TLS settings",
+			"Only one of "+pemAttr+" and "+fileAttr+" can be set.",
+		)
+		return "", fileAttr, diags
+	}
+
+	pem, err := os.ReadFile(fileValue.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(fileAttr),
+			"Unable to read etcd TLS file",
+			"The file could not be read: "+err.Error(),
+		)
+		return "", fileAttr, diags
+	}
+
+	return string(pem), fileAttr, diags
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// headerTransport sets a fixed header on every request before handing it to
+// the wrapped transport, e.g. an Authorization header expected by a gateway.
+type headerTransport struct {
+	clientv2.CancelableTransport
+
+	name  string
+	value string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(t.name, t.value)
+
+	return t.CancelableTransport.RoundTrip(req)
+}
+
+// baseTransport returns a copy of the default transport for configurations
+// that need to change how connections are made.
+func baseTransport() (*http.Transport, error) {
+	base, ok := clientv2.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("default transport is not an *http.Transport")
+	}
+
+	return base.Clone(), nil
+}
+
+// socks5ProxyURL builds the proxy URL for a SOCKS5 proxy at address, such as
+// one forwarded over SSH from a bastion. Names are resolved by the proxy.
+func socks5ProxyURL(address, username, password string) (*url.URL, error) {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, err
+	}
+
+	u := &url.URL{Scheme: "socks5", Host: address}
+
+	if username != "" {
+		u.User = url.UserPassword(username, password)
+	}
+
+	return u, nil
+}
+
+// httpProxyFunc returns the proxy selection of a transport using the given
+// proxies, falling back to HTTP_PROXY, HTTPS_PROXY and NO_PROXY for those
+// left empty.
+func httpProxyFunc(httpProxy, httpsProxy, noProxy string) func(*http.Request) (*url.URL, error) {
+	cfg := httpproxy.FromEnvironment()
+
+	if httpProxy != "" {
+		cfg.HTTPProxy = httpProxy
+	}
+	if httpsProxy != "" {
+		cfg.HTTPSProxy = httpsProxy
+	}
+	if noProxy != "" {
+		cfg.NoProxy = noProxy
+	}
+
+	proxy := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
+
+// validateProxyURL checks that proxy is an http, https or socks5 URL with a
+// host.
+func validateProxyURL(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return err
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("%q must start with http://, https:// or socks5://", proxy)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("%q does not name a host", proxy)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &unmanagedKeysDataSource{}
+	_ datasource.DataSourceWithConfigure = &unmanagedKeysDataSource{}
+)
+
+func NewUnmanagedKeysDataSource() datasource.DataSource {
+	return &unmanagedKeysDataSource{}
+}
+
+type unmanagedKeysDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type unmanagedKeysDataSourceModel struct {
+	Prefix    types.String `tfsdk:"prefix"`
+	ManagedBy types.String `tfsdk:"managed_by"`
+	Keys      types.List   `tfsdk:"keys"`
+}
+
+func (d *unmanagedKeysDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_unmanaged_keys"
+}
+
+func (d *unmanagedKeysDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the keys beneath a prefix whose `_meta` metadata lacks the managed-by marker written by `etcdv2_keyvalue`, " +
+			"so stray keys can be audited and adopted or cleaned up.",
+		Attributes: map[string]schema.Attribute{
+			"prefix": schema.StringAttribute{
+				MarkdownDescription: "The directory to audit (e.g. '/app')",
+				Required:            true,
+			},
+			"managed_by": schema.StringAttribute{
+				MarkdownDescription: "The managed-by value that marks a key as managed. Defaults to `terraform`",
+				Optional:            true,
+			},
+			"keys": schema.ListAttribute{
+				MarkdownDescription: "The unmanaged keys, sorted",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *unmanagedKeysDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data unmanagedKeysDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(d.provider.checkKeyPolicy("prefix", data.Prefix)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	kApi, err := d.provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+
+		return
+	}
+
+	marker := managedByTerraform
+	if !data.ManagedBy.IsNull() {
+		marker = data.ManagedBy.ValueString()
+	}
+
+	prefix, err := kApi.Get(ctx, data.Prefix.ValueString(), &clientv2.GetOptions{Recursive: true, Sort: true})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd prefix",
+			errorDetail(err),
+		)
+		return
+	}
+
+	// Metadata lives in hidden directories, which listings leave out, so
+	// every directory is checked for its own _meta
+	unmanaged := []string{}
+	var walk func(*clientv2.Node) error
+	walk = func(dir *clientv2.Node) error {
+		managed, err := managedKeys(ctx, kApi, dir.Key, marker)
+		if err != nil {
+			return err
+		}
+
+		for _, n := range dir.Nodes {
+			if strings.HasPrefix(path.Base(n.Key), "_") {
+				continue
+			}
+
+			if n.Dir {
+				if err := walk(n); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if !managed[n.Key] {
+				unmanaged = append(unmanaged, n.Key)
+			}
+		}
+
+		return nil
+	}
+
+	if prefix.Node.Dir {
+		err = walk(prefix.Node)
+	} else {
+		var managed map[string]bool
+		managed, err = managedKeys(ctx, kApi, path.Dir(prefix.Node.Key), marker)
+		if err == nil && !managed[prefix.Node.Key] {
+			unmanaged = append(unmanaged, prefix.Node.Key)
+		}
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd key metadata",
+			errorDetail(err),
+		)
+		return
+	}
+	sort.Strings(unmanaged)
+
+	keys, diags := types.ListValueFrom(ctx, types.StringType, unmanaged)
+	resp.Diagnostics.Append(diags...)
+	data.Keys = keys
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *unmanagedKeysDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
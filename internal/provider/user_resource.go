@@ -0,0 +1,403 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"terraform-provider-etcdv2/pkg/etcdv2ops"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &UserResource{}
+	_ resource.ResourceWithConfigure   = &UserResource{}
+	_ resource.ResourceWithImportState = &UserResource{}
+)
+
+func NewUserResource() resource.Resource {
+	return &UserResource{}
+}
+
+// UserResource defines the resource implementation
+type UserResource struct {
+	provider *etcdv2ProviderData
+}
+
+// UserResourceModel describes the resource data model.
+type UserResourceModel struct {
+	Name                 types.String   `tfsdk:"name"`
+	Password             types.String   `tfsdk:"password"`
+	Roles                []types.String `tfsdk:"roles"`
+	KeepOnPartialFailure types.Bool     `tfsdk:"keep_on_partial_failure"`
+	AdoptExisting        types.Bool     `tfsdk:"adopt_existing"`
+}
+
+func (r *UserResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "etcdv2 User resource",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the user",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The password of the user. etcd does not return passwords, so changes made outside of Terraform are not detected",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"roles": schema.SetAttribute{
+				MarkdownDescription: "The roles granted to the user",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Adopt a user that already exists instead of failing, setting its password and roles to match the configuration",
+				Optional:            true,
+			},
+			"keep_on_partial_failure": schema.BoolAttribute{
+				MarkdownDescription: "Keep a user whose roles could not be granted during create, saving it to state with a warning instead of removing it again. The next plan shows the missing roles and the next apply grants them",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *UserResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	r.provider = provider
+}
+
+func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data UserResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	client, err := clientv2.New(r.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Retrieve UserAPI from client
+	uApi := clientv2.NewAuthUserAPI(client)
+
+	defer r.provider.lockUser(data.Name.ValueString())()
+
+	err = uApi.AddUser(ctx, data.Name.ValueString(), data.Password.ValueString())
+	if isAuthExist(err) && data.AdoptExisting.ValueBool() {
+		user, err := etcdv2ops.GetUser(ctx, uApi, data.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Read existing etcd user",
+				errorDetail(err),
+			)
+			return
+		}
+
+		// The existing password is unknown, so it is always set
+		existing := UserResourceModel{Name: data.Name, Password: types.StringNull()}
+		existing.setRoles(user.Roles)
+
+		resp.Diagnostics.Append(data.updateUser(ctx, uApi, &existing)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcd user",
+			errorDetail(err),
+		)
+		return
+	}
+
+	roles := data.roleNames()
+	if len(roles) > 0 {
+		if _, err := uApi.GrantUser(ctx, data.Name.ValueString(), roles); err != nil {
+			if data.KeepOnPartialFailure.ValueBool() {
+				// Save the user without tainting it. Terraform only accepts the
+				// planned roles in state, so those are saved and the next
+				// refresh finds the missing ones for the next apply
+				resp.Diagnostics.AddWarning(
+					"Unable to Grant etcd user roles",
+					"The user "+data.Name.ValueString()+" was created, but its roles could not be granted. "+
+						"It was saved to state, the next plan shows the missing roles and the next apply grants them.\n\n"+
+						"etcdv2 Error: "+errorDetail(err),
+				)
+				resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateKeyPendingGrants, []byte("true"))...)
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+				return
+			}
+
+			resp.Diagnostics.AddError(
+				"Unable to Grant etcd user roles",
+				errorDetail(err),
+			)
+
+			// Roll back so a retry does not fail with the user already existing
+			if rollbackErr := uApi.RemoveUser(ctx, data.Name.ValueString()); rollbackErr != nil {
+				resp.Diagnostics.AddError(
+					"Unable to roll back partially created etcd user",
+					"The user "+data.Name.ValueString()+" exists without its roles and must be removed or imported.\n\n"+
+						"etcdv2 Error: "+errorDetail(rollbackErr),
+				)
+			}
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data UserResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new etcd client from config, reading from the leader so a
+	// grant made just before is not missing from the role list
+	client, err := r.provider.leaderClient(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Retrieve UserAPI from client
+	uApi := clientv2.NewAuthUserAPI(client)
+
+	user, err := etcdv2ops.GetUser(ctx, uApi, data.Name.ValueString())
+	if clientv2.IsUserNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd user",
+			errorDetail(err),
+		)
+		return
+	}
+
+	pending, diags := req.Private.GetKey(ctx, privateKeyPendingGrants)
+	resp.Diagnostics.Append(diags...)
+
+	// Roles missing from a partially created user are not drift
+	if r.provider.drift != nil && string(pending) != "true" {
+		// Granted and revoked relative to state, i.e. by someone else
+		if revoked, granted := etcdv2ops.DiffUserRoles(data.roleNames(), user.Roles); len(granted)+len(revoked) > 0 {
+			resp.Diagnostics.Append(r.provider.drift.recordRoles(data.Name.ValueString(), granted, revoked)...)
+		}
+	}
+
+	data.setRoles(user.Roles)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state UserResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	client, err := clientv2.New(r.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Retrieve UserAPI from client
+	uApi := clientv2.NewAuthUserAPI(client)
+
+	defer r.provider.lockUser(data.Name.ValueString())()
+
+	resp.Diagnostics.Append(data.updateUser(ctx, uApi, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every role is granted now, even if Create only got part way
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateKeyPendingGrants, []byte("false"))...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data UserResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.provider.acquireFence(ctx); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to acquire apply fencing key",
+			"Another apply may be in progress against this cluster.\n\n"+
+				"etcdv2 Error: "+errorDetail(err),
+		)
+		return
+	}
+
+	// Create new etcd client from config
+	client, err := clientv2.New(r.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Retrieve UserAPI from client
+	uApi := clientv2.NewAuthUserAPI(client)
+
+	defer r.provider.lockUser(data.Name.ValueString())()
+
+	err = uApi.RemoveUser(ctx, data.Name.ValueString())
+	if err != nil && !clientv2.IsUserNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error when trying to Delete etcd user",
+			errorDetail(err),
+		)
+		return
+	}
+}
+
+func (r *UserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, diags := r.provider.importID(ctx, req.ID, "name")
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), id)...)
+}
+
+// updateUser changes the password and roles of the user wherever they differ
+// from those in have.
+func (m *UserResourceModel) updateUser(ctx context.Context, uApi clientv2.AuthUserAPI, have *UserResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if err := etcdv2ops.SyncUser(ctx, uApi, m.opsUser(), have.opsUser()); err != nil {
+		diags.AddError(
+			"Unable to Update etcd user",
+			errorDetail(err),
+		)
+	}
+
+	return diags
+}
+
+// opsUser returns the model as an etcdv2ops.User. A null password is unknown.
+func (m *UserResourceModel) opsUser() etcdv2ops.User {
+	user := etcdv2ops.User{Name: m.Name.ValueString(), Roles: m.roleNames()}
+	if !m.Password.IsNull() {
+		password := m.Password.ValueString()
+		user.Password = &password
+	}
+
+	return user
+}
+
+// roleNames returns the roles of the model in sorted order.
+func (m *UserResourceModel) roleNames() []string {
+	roles := make([]string, 0, len(m.Roles))
+	for _, role := range m.Roles {
+		roles = append(roles, role.ValueString())
+	}
+	sort.Strings(roles)
+
+	return roles
+}
+
+// setRoles replaces the model roles with those etcd reports.
+func (m *UserResourceModel) setRoles(roles []string) {
+	// Keep an unset attribute unset when the user has no roles
+	if len(roles) == 0 && m.Roles == nil {
+		return
+	}
+
+	m.Roles = make([]types.String, 0, len(roles))
+	for _, role := range roles {
+		m.Roles = append(m.Roles, types.StringValue(role))
+	}
+}
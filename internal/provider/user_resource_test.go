@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"terraform-provider-etcdv2/internal/etcdv2mock"
+	"terraform-provider-etcdv2/internal/testetcd"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccUserResource(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: etcd.ProviderConfig() + testAccUserResourceConfig("first", `["reader"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("etcdv2_user.test", "name", "app"),
+					resource.TestCheckResourceAttr("etcdv2_user.test", "roles.#", "1"),
+					testAccCheckEtcdUser(etcd, "app", "first", []string{"reader"}),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: etcd.ProviderConfig() + testAccUserResourceConfig("second", `["reader", "writer"]`),
+				Check:  testAccCheckEtcdUser(etcd, "app", "second", []string{"reader", "writer"}),
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := etcd.UserRoles("app"); ok {
+				return fmt.Errorf("user app still exists")
+			}
+
+			return nil
+		},
+	})
+}
+
+// TestAccUserResource_keepOnPartialFailure fails granting the roles of a new
+// user, which is kept so the next apply grants them.
+func TestAccUserResource_keepOnPartialFailure(t *testing.T) {
+	etcd := testetcd.Start(t)
+
+	config := etcd.ProviderConfig() + testAccUserResourceConfig("first", `["reader"]`) + `
+resource "etcdv2_user" "kept" {
+  name     = "kept"
+  password = "first"
+  roles    = ["reader"]
+
+  keep_on_partial_failure = true
+
+  depends_on = [etcdv2_role.reader]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					etcd.Inject(etcdv2mock.Fault{
+						Method: http.MethodPut,
+						Path:   "/v2/auth/users/kept",
+						Status: http.StatusInternalServerError,
+						Body:   etcdv2mock.AuthError{Message: "auth: internal error"},
+						Times:  1,
+						// The first request adds the user
+						After: 1,
+					})
+				},
+				Config: config,
+				Check:  testAccCheckEtcdUser(etcd, "kept", "first", []string{}),
+				// The refresh finds the roles still missing
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check:  testAccCheckEtcdUser(etcd, "kept", "first", []string{"reader"}),
+			},
+		},
+	})
+}
+
+func testAccUserResourceConfig(password, roles string) string {
+	return fmt.Sprintf(`
+resource "etcdv2_role" "reader" {
+  name = "reader"
+
+  permissions = [
+    {
+      key    = "/app/*"
+      access = "read"
+    },
+  ]
+}
+
+resource "etcdv2_role" "writer" {
+  name = "writer"
+
+  permissions = [
+    {
+      key    = "/app/*"
+      access = "write"
+    },
+  ]
+}
+
+resource "etcdv2_user" "test" {
+  name     = "app"
+  password = %[1]q
+  roles    = %[2]s
+
+  depends_on = [etcdv2_role.reader, etcdv2_role.writer]
+}
+`, password, roles)
+}
+
+// testAccCheckEtcdUser checks the password and roles the server holds for
+// user.
+func testAccCheckEtcdUser(etcd *testetcd.Server, name, password string, roles []string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		got, ok := etcd.UserRoles(name)
+		if !ok {
+			return fmt.Errorf("user %s does not exist", name)
+		}
+		if !reflect.DeepEqual(got, roles) {
+			return fmt.Errorf("user %s has roles %v, want %v", name, got, roles)
+		}
+		if p, _ := etcd.Password(name); p != password {
+			return fmt.Errorf("user %s has password %q, want %q", name, p, password)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &validateDataSource{}
+	_ datasource.DataSourceWithConfigure = &validateDataSource{}
+)
+
+func NewValidateDataSource() datasource.DataSource {
+	return &validateDataSource{}
+}
+
+type validateDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type validateDataSourceModel struct {
+	Key        types.String `tfsdk:"key"`
+	JSONSchema types.String `tfsdk:"json_schema"`
+	Valid      types.Bool   `tfsdk:"valid"`
+	Errors     types.List   `tfsdk:"errors"`
+}
+
+func (d *validateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validate"
+}
+
+func (d *validateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Validates the value of a key, or of every key beneath a directory, against a JSON Schema. " +
+			"Violations are reported in `errors` rather than failing the read, so the result can be asserted in `check` blocks.",
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The key to validate. If it is a directory, every key beneath it is validated",
+				Required:            true,
+			},
+			"json_schema": schema.StringAttribute{
+				MarkdownDescription: "The JSON Schema each value must be a valid JSON document for. " +
+					"The keywords `type`, `enum`, `const`, `properties`, `required`, `additionalProperties`, `items`, `minItems`, `maxItems`, " +
+					"`minimum`, `maximum`, `minLength`, `maxLength` and `pattern` are enforced, others are ignored",
+				Required: true,
+			},
+			"valid": schema.BoolAttribute{
+				MarkdownDescription: "Whether every value is valid",
+				Computed:            true,
+			},
+			"errors": schema.ListAttribute{
+				MarkdownDescription: "The violations found, each prefixed with the key it was found in",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *validateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data validateDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(d.provider.checkKeyPolicy("key", data.Key)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	jsonSchema, err := parseJSONSchema(data.JSONSchema.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("json_schema"),
+			"Invalid JSON Schema",
+			err.Error(),
+		)
+		return
+	}
+
+	kApi, err := d.provider.keysAPI()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+
+		return
+	}
+
+	keyvalue, err := kApi.Get(ctx, data.Key.ValueString(), &clientv2.GetOptions{Recursive: true, Sort: true})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read etcd keyvalue",
+			errorDetail(err),
+		)
+		return
+	}
+
+	errs := []string{}
+	var validate func(node *clientv2.Node)
+	validate = func(node *clientv2.Node) {
+		if !node.Dir {
+			for _, e := range validateJSONDocument(jsonSchema, node.Value) {
+				errs = append(errs, node.Key+": "+e)
+			}
+			return
+		}
+		for _, child := range node.Nodes {
+			validate(child)
+		}
+	}
+	validate(keyvalue.Node)
+
+	data.Valid = types.BoolValue(len(errs) == 0)
+
+	list, diags := types.ListValueFrom(ctx, types.StringType, errs)
+	resp.Diagnostics.Append(diags...)
+	data.Errors = list
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *validateDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Types a keyvalue can declare for its value.
+const (
+	valueTypeString = "string"
+	valueTypeInt    = "int"
+	valueTypeBool   = "bool"
+	valueTypeFloat  = "float"
+)
+
+// canonicalValue returns the representation value is stored with for typ:
+// base-10 integers, "true" or "false", and the shortest float that parses
+// back to the same number. Strings are stored as they are.
+func canonicalValue(typ, value string) (string, error) {
+	switch typ {
+	case valueTypeString:
+		return value, nil
+	case valueTypeInt:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a base-10 integer", value)
+		}
+		return strconv.FormatInt(i, 10), nil
+	case valueTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a boolean", value)
+		}
+		return strconv.FormatBool(b), nil
+	case valueTypeFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a number", value)
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unknown type %q, expected one of string, int, bool or float", typ)
+	}
+}
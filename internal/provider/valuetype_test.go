@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestCanonicalValue_idempotent(t *testing.T) {
+	for _, typ := range []string{valueTypeString, valueTypeInt, valueTypeBool, valueTypeFloat} {
+		typ := typ
+		t.Run(typ, func(t *testing.T) {
+			f := func(value string) bool {
+				canonical, err := canonicalValue(typ, value)
+				if err != nil {
+					return true
+				}
+
+				again, err := canonicalValue(typ, canonical)
+				return err == nil && again == canonical
+			}
+			if err := quick.Check(f, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestCanonicalValue_int(t *testing.T) {
+	f := func(i int64, plus bool, zeros uint8) bool {
+		// Leading zeros and an explicit plus sign are dropped
+		sign, digits := "", strconv.FormatInt(i, 10)
+		if i < 0 {
+			sign, digits = "-", digits[1:]
+		} else if plus {
+			sign = "+"
+		}
+		value := sign + strings.Repeat("0", int(zeros%4)) + digits
+
+		canonical, err := canonicalValue(valueTypeInt, value)
+		return err == nil && canonical == strconv.FormatInt(i, 10)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCanonicalValue_float(t *testing.T) {
+	f := func(x float64, format byte) bool {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return true
+		}
+
+		// Any representation of the number canonicalizes to the same value
+		value := strconv.FormatFloat(x, "efg"[int(format)%3], -1, 64)
+		canonical, err := canonicalValue(valueTypeFloat, value)
+		if err != nil {
+			return false
+		}
+
+		parsed, err := strconv.ParseFloat(canonical, 64)
+		return err == nil && parsed == x && canonical == strconv.FormatFloat(x, 'g', -1, 64)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
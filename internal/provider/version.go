@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// versionCheckTimeout bounds the requests made by checkV2API.
+const versionCheckTimeout = 5 * time.Second
+
+// serverVersion is the body of etcd's /version endpoint.
+type serverVersion struct {
+	Server  string `json:"etcdserver"`
+	Cluster string `json:"etcdcluster"`
+}
+
+// atLeast reports whether the server version is major.minor or later.
+func (v serverVersion) atLeast(major, minor int) bool {
+	parts := strings.SplitN(v.Server, ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+
+	gotMajor, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	gotMinor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+
+	return gotMajor > major || (gotMajor == major && gotMinor >= minor)
+}
+
+// checkV2API reads /version and reports whether the cluster still serves the
+// v2 API. etcd 3.4 and later only serve it with --enable-v2, so without this
+// every resource would fail with an opaque 404. Clusters that cannot be
+// reached are left for the first request to report.
+func checkV2API(ctx context.Context, cfg clientv2.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client, err := clientv2.New(cfg)
+	if err != nil {
+		return diags
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, versionCheckTimeout)
+	defer cancel()
+
+	resp, body, err := client.Do(ctx, &getPathAction{path: "/version"})
+	if err != nil || resp.StatusCode != http.StatusOK {
+		tflog.Debug(ctx, "Unable to read etcd version", map[string]interface{}{"error": fmt.Sprint(err)})
+		return diags
+	}
+
+	var version serverVersion
+	if err := json.Unmarshal(body, &version); err != nil {
+		tflog.Debug(ctx, "Unable to parse etcd version", map[string]interface{}{"error": err.Error()})
+		return diags
+	}
+
+	if !version.atLeast(3, 4) {
+		return diags
+	}
+
+	resp, _, err = client.Do(ctx, &getPathAction{path: "/v2/keys/"})
+	if err == nil && resp.StatusCode == http.StatusNotFound {
+		diags.AddError(
+			"etcd v2 API unavailable",
+			fmt.Sprintf("The cluster runs etcd %s, which does not serve the v2 API unless started with --enable-v2. "+
+				"etcd 3.6 removes the v2 API entirely, so the data must be migrated to the v3 API.", version.Server),
+		)
+		return diags
+	}
+
+	diags.AddWarning(
+		"etcd v2 API is deprecated",
+		fmt.Sprintf("The cluster runs etcd %s, where the v2 API is deprecated and only served with --enable-v2. "+
+			"etcd 3.6 removes it entirely.", version.Server),
+	)
+
+	return diags
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &waitForMembersDataSource{}
+	_ datasource.DataSourceWithConfigure = &waitForMembersDataSource{}
+)
+
+func NewWaitForMembersDataSource() datasource.DataSource {
+	return &waitForMembersDataSource{}
+}
+
+type waitForMembersDataSource struct {
+	provider *etcdv2ProviderData
+}
+
+type waitForMembersDataSourceModel struct {
+	Members    types.Int64 `tfsdk:"members"`
+	Timeout    types.Int64 `tfsdk:"timeout"`
+	Started    types.Int64 `tfsdk:"started"`
+	ClientURLs types.List  `tfsdk:"client_urls"`
+}
+
+func (d *waitForMembersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_wait_for_members"
+}
+
+func (d *waitForMembersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Waits until the cluster reports enough started members, so etcd nodes can be provisioned " +
+			"and seeded with configuration in a single apply.",
+		Attributes: map[string]schema.Attribute{
+			"members": schema.Int64Attribute{
+				MarkdownDescription: "The number of started members to wait for. A member counts as started once it has client URLs",
+				Required:            true,
+			},
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "How long in seconds to wait for the members. Defaults to 300",
+				Optional:            true,
+			},
+			"started": schema.Int64Attribute{
+				MarkdownDescription: "The number of started members found",
+				Computed:            true,
+			},
+			"client_urls": schema.ListAttribute{
+				MarkdownDescription: "The client URLs of the started members, sorted",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *waitForMembersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data waitForMembersDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := clientv2.New(d.provider.clientConfig())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create etcdv2 API client",
+			"An unexpected error occurred when creating the etcdv2 API client.\n\n"+
+				"etcdv2 Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	mApi := clientv2.NewMembersAPI(client)
+
+	timeout := defaultReadyTimeout
+	if !data.Timeout.IsNull() {
+		timeout = time.Duration(data.Timeout.ValueInt64()) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var urls []string
+	for {
+		var started int64
+		urls, started, err = startedMembers(ctx, mApi)
+		if err == nil && started >= data.Members.ValueInt64() {
+			data.Started = types.Int64Value(started)
+			break
+		}
+		if err == nil {
+			err = fmt.Errorf("%d of %d members started", started, data.Members.ValueInt64())
+		}
+		tflog.Debug(ctx, "Waiting for etcd members", map[string]interface{}{"reason": err.Error()})
+
+		select {
+		case <-ctx.Done():
+			resp.Diagnostics.AddError(
+				"Timed out waiting for etcd members",
+				fmt.Sprintf("The cluster did not report enough started members within %s: %s", timeout, err),
+			)
+			return
+		case <-time.After(readyPollInterval):
+		}
+	}
+
+	list, diags := types.ListValueFrom(ctx, types.StringType, urls)
+	resp.Diagnostics.Append(diags...)
+	data.ClientURLs = list
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// startedMembers returns the sorted client URLs of the members that have
+// started, along with how many there are.
+func startedMembers(ctx context.Context, mApi clientv2.MembersAPI) ([]string, int64, error) {
+	members, err := mApi.List(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	urls := []string{}
+	var started int64
+	for _, member := range members {
+		if len(member.ClientURLs) == 0 {
+			continue
+		}
+		started++
+		urls = append(urls, member.ClientURLs...)
+	}
+	sort.Strings(urls)
+
+	return urls, started, nil
+}
+
+func (d *waitForMembersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider := req.ProviderData.(*etcdv2ProviderData)
+
+	d.provider = provider
+}
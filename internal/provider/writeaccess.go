@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+
+	"terraform-provider-etcdv2/pkg/etcdv2ops"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// writeAccess holds the write permissions of the provider's own user, read
+// once per cluster when check_write_permissions is enabled.
+type writeAccess struct {
+	// tokenAuth is set when auth_header or bearer_token authenticates, whose
+	// user the provider cannot know
+	tokenAuth bool
+
+	once sync.Once
+
+	// unrestricted is set when auth is disabled or the user holds root
+	unrestricted bool
+	user         string
+	roles        []string
+	patterns     []string
+
+	// err is set when the permissions could not be read
+	err error
+}
+
+// load reads the roles of the configured user, or of guest without one. etcd
+// only answers the auth API for root, so other users usually cannot verify
+// their own permissions and the check is skipped with a warning.
+func (w *writeAccess) load(ctx context.Context, p *etcdv2ProviderData) {
+	if w.tokenAuth && p.cfg.Username == "" {
+		w.err = errors.New("the user authenticated by auth_header or bearer_token is not known to the provider")
+		return
+	}
+
+	client, err := clientv2.New(p.clientConfig())
+	if err != nil {
+		w.err = err
+		return
+	}
+
+	enabled, err := readAuthEnabled(ctx, client)
+	if err != nil {
+		w.err = err
+		return
+	}
+	if !enabled {
+		w.unrestricted = true
+		return
+	}
+
+	w.user = p.cfg.Username
+	switch w.user {
+	case "root":
+		w.unrestricted = true
+		return
+	case "":
+		w.user, w.roles = "guest", []string{"guest"}
+	default:
+		user, err := etcdv2ops.GetUser(ctx, clientv2.NewAuthUserAPI(client), w.user)
+		if err != nil {
+			w.err = err
+			return
+		}
+		w.roles = user.Roles
+	}
+
+	rApi := clientv2.NewAuthRoleAPI(client)
+	for _, name := range w.roles {
+		if name == "root" {
+			w.unrestricted = true
+			return
+		}
+
+		role, err := rApi.GetRole(ctx, name)
+		if err != nil {
+			w.err = err
+			return
+		}
+		w.patterns = append(w.patterns, role.Permissions.KV.Write...)
+	}
+}
+
+// checkWriteAccess fails when check_write_permissions is enabled and none of
+// the roles of the provider's user grant write access to key.
+func (p *etcdv2ProviderData) checkWriteAccess(ctx context.Context, attribute string, key types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if p == nil || p.writeAccess == nil || key.IsNull() || key.IsUnknown() {
+		return diags
+	}
+
+	w := p.writeAccess
+	w.once.Do(func() {
+		w.load(ctx, p)
+
+		if w.err != nil {
+			diags.AddWarning(
+				"Unable to verify etcd write permissions",
+				"check_write_permissions is enabled, but the roles of the provider's user could not be read, "+
+					"so planned keys are not checked against them.\n\n"+
+					"etcdv2 Error: "+errorDetail(w.err),
+			)
+		}
+	})
+
+	if w.err != nil || w.unrestricted {
+		return diags
+	}
+
+	if !anyPermissionMatches(w.patterns, key.ValueString()) {
+		roles := "no roles"
+		if len(w.roles) > 0 {
+			roles = "the roles " + strings.Join(w.roles, ", ")
+		}
+
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Insufficient etcd permissions",
+			"The provider's user "+w.user+" holds "+roles+", none of which grant write access to "+key.ValueString()+", "+
+				"so applying this change would fail.",
+		)
+	}
+
+	return diags
+}
@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package testetcd starts disposable etcd v2 API servers for acceptance
+// tests. Servers are backed by etcdv2mock, so tests can inspect the keyspace
+// and inject faults, and can require TLS or basic authentication.
+package testetcd
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"terraform-provider-etcdv2/internal/etcdv2mock"
+)
+
+// Server is a running etcd v2 API server. It is closed when the test that
+// started it finishes.
+type Server struct {
+	*etcdv2mock.Server
+
+	// URL is the base URL of the server, e.g. "http://127.0.0.1:41223".
+	URL string
+
+	// CACertPEM is the PEM encoded certificate the server is trusted with, or
+	// "" if it does not serve TLS.
+	CACertPEM string
+
+	// AuthUsername and AuthPassword are the credentials every request must
+	// carry, or "" if the server does not require authentication.
+	AuthUsername string
+	AuthPassword string
+
+	mu       sync.Mutex
+	requests map[string]int
+	hooks    map[string]func()
+}
+
+type options struct {
+	tls      bool
+	username string
+	password string
+}
+
+// Option configures a server started with Start.
+type Option func(*options)
+
+// WithTLS serves the API over TLS with a self-signed certificate.
+func WithTLS() Option {
+	return func(o *options) {
+		o.tls = true
+	}
+}
+
+// WithAuth rejects every request not authenticated as username and
+// password, as etcd does with auth enabled and the guest role removed.
+func WithAuth(username, password string) Option {
+	return func(o *options) {
+		o.username = username
+		o.password = password
+	}
+}
+
+// Start starts a server with an empty keyspace.
+func Start(t testing.TB, opts ...Option) *Server {
+	t.Helper()
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &Server{
+		Server:       etcdv2mock.New(),
+		AuthUsername: o.username,
+		AuthPassword: o.password,
+		requests:     make(map[string]int),
+		hooks:        make(map[string]func()),
+	}
+
+	var handler http.Handler = s.count(s.Server)
+	if o.username != "" {
+		handler = s.authenticate(handler)
+	}
+
+	var srv *httptest.Server
+	if o.tls {
+		srv = httptest.NewTLSServer(handler)
+		s.CACertPEM = string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: srv.Certificate().Raw,
+		}))
+	} else {
+		srv = httptest.NewServer(handler)
+	}
+	t.Cleanup(srv.Close)
+
+	s.URL = srv.URL
+
+	return s
+}
+
+// Requests returns how many requests with method were made to path, e.g.
+// "/v2/auth/roles/app".
+func (s *Server) Requests(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[method+" "+path]
+}
+
+// BeforeNext runs hook right before the next request with method to path is
+// handled, e.g. to write a key concurrently with the provider.
+func (s *Server) BeforeNext(method, path string, hook func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hooks[method+" "+path] = hook
+}
+
+func (s *Server) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.Path
+
+		s.mu.Lock()
+		s.requests[request]++
+		hook := s.hooks[request]
+		delete(s.hooks, request)
+		s.mu.Unlock()
+
+		if hook != nil {
+			hook()
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != s.AuthUsername || password != s.AuthPassword {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"Insufficient credentials"}`)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ProviderConfig returns an etcdv2 provider block connecting to the server.
+// Any extra lines are added to the block as they are.
+func (s *Server) ProviderConfig(extra ...string) string {
+	lines := []string{fmt.Sprintf("host = %q", s.URL)}
+
+	if s.CACertPEM != "" {
+		lines = append(lines, fmt.Sprintf("ca_cert_pem = %q", s.CACertPEM))
+	}
+
+	if s.AuthUsername != "" {
+		lines = append(lines,
+			fmt.Sprintf("username = %q", s.AuthUsername),
+			fmt.Sprintf("password = %q", s.AuthPassword),
+		)
+	}
+
+	lines = append(lines, extra...)
+
+	return "provider \"etcdv2\" {\n  " + strings.Join(lines, "\n  ") + "\n}\n"
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 
@@ -23,6 +24,10 @@ import (
 // can be customized.
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs
 
+// shutdownTimeout bounds releasing keys on exit, as Terraform kills plugins
+// that do not exit within 2 seconds of being told to stop.
+const shutdownTimeout = 1500 * time.Millisecond
+
 var (
 	// these will be set by the goreleaser configuration
 	// to appropriate values for the compiled binary.
@@ -46,6 +51,11 @@ func main() {
 
 	err := providerserver.Serve(context.Background(), provider.New(version), opts)
 
+	// Release apply scoped keys before the plugin process is killed
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	provider.Shutdown(ctx)
+	cancel()
+
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -0,0 +1,4 @@
+// Package etcdv2ops holds the etcd v2 operations behind the etcdv2 Terraform
+// provider, so other tools can converge keys, roles and users with exactly
+// the semantics the provider applies.
+package etcdv2ops
@@ -0,0 +1,80 @@
+package etcdv2ops
+
+import (
+	"context"
+	"fmt"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// EnsureKeyOptions controls how EnsureKey treats a key that already exists.
+type EnsureKeyOptions struct {
+	// KeepExisting leaves an existing key as it is, so the value only seeds
+	// a missing key.
+	KeepExisting bool
+
+	// Adopt overwrites an existing key, conditional on it not changing while
+	// it is taken over.
+	Adopt bool
+
+	// EmptyAsMissing treats an existing key with an empty value as absent.
+	EmptyAsMissing bool
+}
+
+// EnsureKey creates key with value. An existing key fails the create unless
+// opts allow it. kept reports that KeepExisting left the key untouched, in
+// which case resp is the read of the existing key.
+func EnsureKey(ctx context.Context, kApi clientv2.KeysAPI, key, value string, opts EnsureKeyOptions) (resp *clientv2.Response, kept bool, err error) {
+	if opts.EmptyAsMissing {
+		resp, err = createReplacingEmpty(ctx, kApi, key, value)
+	} else {
+		resp, err = kApi.Create(ctx, key, value)
+	}
+	if !IsNodeExist(err) || !(opts.KeepExisting || opts.Adopt) {
+		return resp, false, err
+	}
+
+	existing, err := kApi.Get(ctx, key, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("reading existing key %s: %w", key, err)
+	}
+
+	if opts.KeepExisting {
+		return existing, true, nil
+	}
+
+	resp, err = kApi.Set(ctx, key, value, &clientv2.SetOptions{
+		PrevExist: clientv2.PrevExist,
+		PrevIndex: existing.Node.ModifiedIndex,
+	})
+
+	return resp, false, err
+}
+
+// IsNodeExist reports whether err is etcd refusing to create an existing key.
+func IsNodeExist(err error) bool {
+	if clientErr, ok := err.(clientv2.Error); ok {
+		return clientErr.Code == clientv2.ErrorCodeNodeExist
+	}
+	return false
+}
+
+// createReplacingEmpty creates key, treating an existing empty value as if the
+// key were absent. Any other existing value fails as Create would.
+func createReplacingEmpty(ctx context.Context, kApi clientv2.KeysAPI, key, value string) (*clientv2.Response, error) {
+	existing, err := kApi.Get(ctx, key, nil)
+	if clientv2.IsKeyNotFound(err) {
+		return kApi.Create(ctx, key, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.Node.Dir || existing.Node.Value != "" {
+		return kApi.Create(ctx, key, value)
+	}
+
+	return kApi.Set(ctx, key, value, &clientv2.SetOptions{
+		PrevIndex: existing.Node.ModifiedIndex,
+	})
+}
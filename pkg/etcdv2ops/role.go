@@ -0,0 +1,150 @@
+package etcdv2ops
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// batchOrder is the order permission batches are applied in.
+var batchOrder = []clientv2.PermissionType{
+	clientv2.ReadWritePermission,
+	clientv2.ReadPermission,
+	clientv2.WritePermission,
+}
+
+// GrantRole grants perms to the role name, in at most one request per
+// permission type. On failure it returns the permissions granted so far, so
+// the caller can record or roll back a partially granted role.
+func GrantRole(ctx context.Context, rApi clientv2.AuthRoleAPI, name string, perms clientv2.Permissions) (clientv2.Permissions, error) {
+	batches := permissionBatches(toSet(perms.KV.Read), toSet(perms.KV.Write))
+
+	var granted clientv2.Permissions
+	for _, permType := range batchOrder {
+		if len(batches[permType]) == 0 {
+			continue
+		}
+
+		if _, err := rApi.GrantRoleKV(ctx, name, batches[permType], permType); err != nil {
+			return granted, err
+		}
+
+		if permType != clientv2.WritePermission {
+			granted.KV.Read = append(granted.KV.Read, batches[permType]...)
+		}
+		if permType != clientv2.ReadPermission {
+			granted.KV.Write = append(granted.KV.Write, batches[permType]...)
+		}
+	}
+
+	return granted, nil
+}
+
+// SyncRole revokes and grants whatever differs between the permissions the
+// role name has and those it should have, batched per permission type.
+// Revokes are applied before grants.
+func SyncRole(ctx context.Context, rApi clientv2.AuthRoleAPI, name string, want, have clientv2.Permissions) error {
+	grant, revoke := DiffRole(want, have)
+
+	revokes := permissionBatches(toSet(revoke.KV.Read), toSet(revoke.KV.Write))
+	grants := permissionBatches(toSet(grant.KV.Read), toSet(grant.KV.Write))
+
+	for _, permType := range batchOrder {
+		if len(revokes[permType]) == 0 {
+			continue
+		}
+
+		if _, err := rApi.RevokeRoleKV(ctx, name, revokes[permType], permType); err != nil {
+			return fmt.Errorf("revoking permissions of role %s: %w", name, err)
+		}
+	}
+
+	for _, permType := range batchOrder {
+		if len(grants[permType]) == 0 {
+			continue
+		}
+
+		if _, err := rApi.GrantRoleKV(ctx, name, grants[permType], permType); err != nil {
+			return fmt.Errorf("granting permissions to role %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// DiffRole returns the permissions that have to be granted and revoked for a
+// role with the permissions have to end up with want. Key patterns are
+// sorted.
+func DiffRole(want, have clientv2.Permissions) (grant, revoke clientv2.Permissions) {
+	wantRead, wantWrite := toSet(want.KV.Read), toSet(want.KV.Write)
+	haveRead, haveWrite := toSet(have.KV.Read), toSet(have.KV.Write)
+
+	grant.KV.Read = sortedKeys(difference(wantRead, haveRead))
+	grant.KV.Write = sortedKeys(difference(wantWrite, haveWrite))
+	revoke.KV.Read = sortedKeys(difference(haveRead, wantRead))
+	revoke.KV.Write = sortedKeys(difference(haveWrite, wantWrite))
+
+	return grant, revoke
+}
+
+// permissionBatches groups key patterns by the permission type they need, so
+// that a role can be granted or revoked any number of patterns in at most one
+// request per permission type.
+func permissionBatches(read, write map[string]bool) map[clientv2.PermissionType][]string {
+	batches := make(map[clientv2.PermissionType][]string)
+
+	for key := range read {
+		if write[key] {
+			batches[clientv2.ReadWritePermission] = append(batches[clientv2.ReadWritePermission], key)
+		} else {
+			batches[clientv2.ReadPermission] = append(batches[clientv2.ReadPermission], key)
+		}
+	}
+
+	for key := range write {
+		if !read[key] {
+			batches[clientv2.WritePermission] = append(batches[clientv2.WritePermission], key)
+		}
+	}
+
+	for permType := range batches {
+		sort.Strings(batches[permType])
+	}
+
+	return batches
+}
+
+// toSet returns the values as a set.
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+
+	return set
+}
+
+// difference returns the keys of a that are not in b.
+func difference(a, b map[string]bool) map[string]bool {
+	out := make(map[string]bool)
+	for key := range a {
+		if !b[key] {
+			out[key] = true
+		}
+	}
+
+	return out
+}
+
+// sortedKeys returns the keys of set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
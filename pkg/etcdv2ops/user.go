@@ -0,0 +1,74 @@
+package etcdv2ops
+
+import (
+	"context"
+	"fmt"
+
+	clientv2 "go.etcd.io/etcd/client/v2"
+)
+
+// User is the password and roles a user should have.
+type User struct {
+	Name string
+
+	// Password is nil when it is unknown. A nil password in want leaves the
+	// password unchanged, in have it means the password is always set.
+	Password *string
+
+	Roles []string
+}
+
+// GetUser returns the user name. etcd reports the roles of a user as full
+// role objects, which the client only decodes after first filling the roles
+// with an empty name for each of them, so those are dropped.
+func GetUser(ctx context.Context, uApi clientv2.AuthUserAPI, name string) (*clientv2.User, error) {
+	user, err := uApi.GetUser(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := user.Roles[:0]
+	for _, role := range user.Roles {
+		if role != "" {
+			roles = append(roles, role)
+		}
+	}
+	user.Roles = roles
+
+	return user, nil
+}
+
+// SyncUser changes the password and roles of want.Name wherever they differ
+// from those in have. Roles are revoked before any are granted.
+func SyncUser(ctx context.Context, uApi clientv2.AuthUserAPI, want, have User) error {
+	if want.Password != nil && (have.Password == nil || *want.Password != *have.Password) {
+		if _, err := uApi.ChangePassword(ctx, want.Name, *want.Password); err != nil {
+			return fmt.Errorf("changing password of user %s: %w", want.Name, err)
+		}
+	}
+
+	grants, revokes := DiffUserRoles(want.Roles, have.Roles)
+
+	if len(revokes) > 0 {
+		if _, err := uApi.RevokeUser(ctx, want.Name, revokes); err != nil {
+			return fmt.Errorf("revoking roles of user %s: %w", want.Name, err)
+		}
+	}
+
+	if len(grants) > 0 {
+		if _, err := uApi.GrantUser(ctx, want.Name, grants); err != nil {
+			return fmt.Errorf("granting roles to user %s: %w", want.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// DiffUserRoles returns the roles in want but not in have, and those in have
+// but not in want, both sorted.
+func DiffUserRoles(want, have []string) (grant, revoke []string) {
+	wantRoles := toSet(want)
+	haveRoles := toSet(have)
+
+	return sortedKeys(difference(wantRoles, haveRoles)), sortedKeys(difference(haveRoles, wantRoles))
+}